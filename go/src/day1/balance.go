@@ -0,0 +1,358 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"sort"
+)
+
+type balanceObjective int
+
+// The redistribution can either make sure nobody carries too much,
+// or spread the food as evenly as possible over all the Elves
+const (
+	minimiseMax balanceObjective = iota
+	minimiseVariance
+)
+
+// exactLimit is the number of assignments up to which
+// the exact solver is used instead of the heuristic
+const exactLimit = 5_000_000
+
+// foodItem is a single item of the inventory and the Elf carrying it.
+// Owners are indices into the slice of Elves.
+type foodItem struct {
+	calories int
+	original int
+}
+
+// transfer is an item handed from one Elf to another
+type transfer struct {
+	calories int
+	from     int
+	to       int
+}
+
+// redistribution is a plan for who carries which item
+type redistribution struct {
+	items     []foodItem
+	owners    []int
+	before    []int
+	after     []int
+	transfers []transfer
+	exact     bool
+}
+
+func flattenItems(elves []elf) []foodItem {
+	items := []foodItem{}
+	for i, e := range elves {
+		for _, calories := range e.items {
+			items = append(items, foodItem{calories, i})
+		}
+	}
+	return items
+}
+
+func carriedTotals(items []foodItem, owners []int, elves int) []int {
+	totals := make([]int, elves)
+	for i, item := range items {
+		totals[owners[i]] += item.calories
+	}
+	return totals
+}
+
+func sumOfSquares(totals []int) int {
+	sum := 0
+	for _, t := range totals {
+		sum += t * t
+	}
+	return sum
+}
+
+func maxTotal(totals []int) int {
+	most := 0
+	for _, t := range totals {
+		if t > most {
+			most = t
+		}
+	}
+	return most
+}
+
+// balancer is the state of the local search
+type balancer struct {
+	items    []foodItem
+	owners   []int
+	totals   []int
+	carried  [][]int
+	moved    int
+	maxMoves int
+}
+
+// movedDelta is how much an operation changes the number of moved items
+func (b *balancer) movedDelta(item int, to int) int {
+	delta := 0
+	if b.owners[item] != b.items[item].original {
+		delta--
+	}
+	if to != b.items[item].original {
+		delta++
+	}
+	return delta
+}
+
+func (b *balancer) move(item int, to int) {
+	from := b.owners[item]
+	b.moved += b.movedDelta(item, to)
+	for i, carried := range b.carried[from] {
+		if carried == item {
+			b.carried[from] = append(b.carried[from][:i], b.carried[from][i+1:]...)
+			break
+		}
+	}
+	b.carried[to] = append(b.carried[to], item)
+	b.owners[item] = to
+	b.totals[from] -= b.items[item].calories
+	b.totals[to] += b.items[item].calories
+}
+
+// improve looks for the best single move or swap from the heavy Elf to the
+// light one. Handing over d calories with 0 < d < gap brings both totals
+// strictly between the old ones, which lowers the variance and never raises
+// the maximum. The closer d is to half the gap, the better.
+func (b *balancer) improve(heavy int, light int) bool {
+	gap := b.totals[heavy] - b.totals[light]
+	bestScore := -1
+	bestGive, bestTake := -1, -1
+
+	consider := func(give int, take int, d int) {
+		if d <= 0 || d >= gap {
+			return
+		}
+		delta := b.movedDelta(give, light)
+		if take >= 0 {
+			delta += b.movedDelta(take, heavy)
+		}
+		if b.maxMoves >= 0 && b.moved+delta > b.maxMoves {
+			return
+		}
+		// twice the distance from the middle of the gap
+		score := gap - 2*d
+		if score < 0 {
+			score = -score
+		}
+		if bestScore == -1 || score < bestScore {
+			bestScore, bestGive, bestTake = score, give, take
+		}
+	}
+
+	for _, give := range b.carried[heavy] {
+		consider(give, -1, b.items[give].calories)
+		for _, take := range b.carried[light] {
+			consider(give, take, b.items[give].calories-b.items[take].calories)
+		}
+	}
+
+	if bestGive == -1 {
+		return false
+	}
+	b.move(bestGive, light)
+	if bestTake >= 0 {
+		b.move(bestTake, heavy)
+	}
+	return true
+}
+
+// step performs one improving operation, trying the heaviest Elf first.
+// Only the heaviest Elf can lower the maximum, the variance objective
+// also tries the other Elves once the heaviest one is stuck.
+func (b *balancer) step(objective balanceObjective) bool {
+	order := make([]int, len(b.totals))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return b.totals[order[i]] > b.totals[order[j]]
+	})
+
+	sources := order[:1]
+	if objective == minimiseVariance {
+		sources = order
+	}
+	for _, heavy := range sources {
+		for i := len(order) - 1; i >= 0 && b.totals[order[i]] < b.totals[heavy]; i-- {
+			if b.improve(heavy, order[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// balanceHeuristic starts from the current loads and keeps moving or
+// swapping items between heavy and light Elves while it helps
+func balanceHeuristic(elves []elf, items []foodItem, objective balanceObjective, maxMoves int) []int {
+	b := &balancer{
+		items:    items,
+		owners:   make([]int, len(items)),
+		totals:   make([]int, len(elves)),
+		carried:  make([][]int, len(elves)),
+		maxMoves: maxMoves,
+	}
+	for i, item := range items {
+		b.owners[i] = item.original
+		b.totals[item.original] += item.calories
+		b.carried[item.original] = append(b.carried[item.original], i)
+	}
+
+	for b.step(objective) {
+	}
+	return b.owners
+}
+
+// balanceExact tries every assignment of items to Elves with branch and
+// bound. Items are placed from the biggest down so bad branches die early.
+func balanceExact(elves []elf, items []foodItem, objective balanceObjective, maxMoves int) []int {
+	order := make([]int, len(items))
+	for i := range order {
+		order[i] = i
+	}
+	sort.Slice(order, func(i, j int) bool {
+		return items[order[i]].calories > items[order[j]].calories
+	})
+
+	owners := make([]int, len(items))
+	best := make([]int, len(items))
+	totals := make([]int, len(elves))
+	bestScore, bestMoves := math.MaxInt, math.MaxInt
+
+	score := func() int {
+		if objective == minimiseMax {
+			return maxTotal(totals)
+		}
+		return sumOfSquares(totals)
+	}
+
+	var search func(depth int, moved int)
+	search = func(depth int, moved int) {
+		// totals only grow as items are placed
+		current := score()
+		if current > bestScore || (current == bestScore && moved >= bestMoves) {
+			return
+		}
+		if depth == len(order) {
+			bestScore, bestMoves = current, moved
+			copy(best, owners)
+			return
+		}
+
+		item := order[depth]
+		for e := range elves {
+			cost := 0
+			if e != items[item].original {
+				cost = 1
+			}
+			if maxMoves >= 0 && moved+cost > maxMoves {
+				continue
+			}
+			owners[item] = e
+			totals[e] += items[item].calories
+			search(depth+1, moved+cost)
+			totals[e] -= items[item].calories
+		}
+	}
+	search(0, 0)
+
+	return best
+}
+
+func exactFeasible(elves int, items int) bool {
+	assignments := 1.0
+	for i := 0; i < items; i++ {
+		assignments *= float64(elves)
+	}
+	return assignments <= exactLimit
+}
+
+func planRedistribution(elves []elf, objective balanceObjective, maxMoves int) redistribution {
+	items := flattenItems(elves)
+	plan := redistribution{items: items}
+
+	if exactFeasible(len(elves), len(items)) {
+		plan.owners = balanceExact(elves, items, objective, maxMoves)
+		plan.exact = true
+	} else {
+		plan.owners = balanceHeuristic(elves, items, objective, maxMoves)
+	}
+
+	original := make([]int, len(items))
+	for i, item := range items {
+		original[i] = item.original
+		if plan.owners[i] != item.original {
+			plan.transfers = append(plan.transfers, transfer{item.calories, item.original, plan.owners[i]})
+		}
+	}
+	plan.before = carriedTotals(items, original, len(elves))
+	plan.after = carriedTotals(items, plan.owners, len(elves))
+	return plan
+}
+
+func printLoads(name string, totals []int) {
+	sorted := make([]int, len(totals))
+	copy(sorted, totals)
+	sort.Ints(sorted)
+
+	m := mean(sorted)
+	variance := 0.0
+	for _, t := range sorted {
+		variance += (float64(t) - m) * (float64(t) - m)
+	}
+	variance /= float64(len(sorted))
+
+	fmt.Printf("%s: max %d, min %d, mean %.1f, std dev %.1f\n",
+		name, sorted[len(sorted)-1], sorted[0], m, math.Sqrt(variance))
+}
+
+func printRedistribution(elves []elf, plan redistribution) {
+	if len(elves) == 0 {
+		fmt.Println("No Elves in the inventory")
+		return
+	}
+
+	if plan.exact {
+		fmt.Println("Solver: exact")
+	} else {
+		fmt.Println("Solver: heuristic")
+	}
+	fmt.Println("Transfers:", len(plan.transfers))
+	for _, t := range plan.transfers {
+		fmt.Printf("  %d calories from Elf %d to Elf %d\n", t.calories, elves[t.from].index, elves[t.to].index)
+	}
+
+	fmt.Println()
+	printLoads("Before", plan.before)
+	printLoads("After ", plan.after)
+
+	fmt.Println()
+	for i, e := range elves {
+		if plan.before[i] != plan.after[i] {
+			fmt.Printf("Elf %d: %d -> %d\n", e.index, plan.before[i], plan.after[i])
+		}
+	}
+}
+
+func balanceReport(filePath string, objective balanceObjective, maxMoves int) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	elves, _, err := parseInventory(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printRedistribution(elves, planRedistribution(elves, objective, maxMoves))
+}
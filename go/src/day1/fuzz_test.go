@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"parsing"
+	"path/filepath"
+	"sort"
+	"testing"
+)
+
+const example = `1000
+2000
+3000
+
+4000
+
+5000
+6000
+
+7000
+8000
+9000
+
+10000
+`
+
+// addInputs seeds the fuzzer with the puzzle example and the committed input
+func addInputs(f *testing.F) {
+	f.Add([]byte(example))
+	if input, err := os.ReadFile("../../input/day1.txt"); err == nil {
+		f.Add(input)
+	}
+}
+
+// checkError makes sure a rejected inventory says which line is wrong
+func checkError(t *testing.T, name string, err error) {
+	t.Helper()
+	var parseErr *parsing.Error
+	if !errors.As(err, &parseErr) && !errors.Is(err, bufio.ErrTooLong) {
+		t.Fatalf("%s: untyped error %v", name, err)
+	}
+	if parseErr != nil && parseErr.Line < 1 {
+		t.Fatalf("%s: error without a line: %v", name, err)
+	}
+}
+
+// FuzzInventory checks that the strict inventory parser and the streaming
+// top K agree on the three biggest totals whenever both accept the input
+func FuzzInventory(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		elves, _, err := parseInventory(bytes.NewReader(data))
+		if err != nil {
+			checkError(t, "parseInventory", err)
+			return
+		}
+		for _, e := range elves {
+			if len(e.items) == 0 || e.firstLine > e.lastLine {
+				t.Fatalf("elf %d has no items or lines %d-%d", e.index, e.firstLine, e.lastLine)
+			}
+		}
+
+		h, err := topKCalories(bytes.NewReader(data), 3)
+		if err != nil {
+			// the streaming parser is stricter about whitespace
+			checkError(t, "topKCalories", err)
+			return
+		}
+		totals := sortedTotals(elves)
+		sort.Sort(sort.Reverse(sort.IntSlice(totals)))
+		want := 0
+		for i := 0; i < 3 && i < len(totals); i++ {
+			want += totals[i]
+		}
+		if got := heapSum(h); got != want {
+			t.Fatalf("top 3 is %d with the heap but %d with the inventory", got, want)
+		}
+	})
+}
+
+// FuzzSolvers runs the original solutions, the bounded top K and the
+// parallel top K on the same inventory. They must fail on the same line
+// or agree on the answer.
+func FuzzSolvers(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		filePath := filepath.Join(t.TempDir(), "inventory.txt")
+		if err := os.WriteFile(filePath, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		maxCalories, maxErr := getMaxCalories(bytes.NewReader(data))
+		topK, topKErr := topKCaloriesSum(bytes.NewReader(data), 3)
+		top, topErr := boundedTopKSum(bytes.NewReader(data), 1)
+		bounded, boundedErr := boundedTopKSum(bytes.NewReader(data), 3)
+		parallel, parallelErr := parallelTopKSum(filePath, 3, 3)
+
+		errs := map[string]error{
+			"getMaxCalories":  maxErr,
+			"topKCaloriesSum": topKErr,
+			"parallelTopKSum": parallelErr,
+		}
+		for name, err := range errs {
+			if (err == nil) != (boundedErr == nil) {
+				t.Fatalf("%s returned %v, boundedTopKSum %v", name, err, boundedErr)
+			}
+			if err != nil {
+				checkError(t, name, err)
+				if err.Error() != boundedErr.Error() {
+					t.Fatalf("%s returned %v, boundedTopKSum %v", name, err, boundedErr)
+				}
+			}
+		}
+		if boundedErr != nil || topErr != nil {
+			return
+		}
+
+		if parallel != bounded {
+			t.Fatalf("parallel top 3 is %d, expected %d", parallel, bounded)
+		}
+		// the original solutions count an Elf for every blank line, even
+		// without any food, so they only agree when no total is negative
+		if bytes.Contains(data, []byte("-")) {
+			return
+		}
+		if maxCalories != top {
+			t.Fatalf("max calories %d, expected %d", maxCalories, top)
+		}
+		if topK != bounded {
+			t.Fatalf("top 3 is %d with the max heap, expected %d", topK, bounded)
+		}
+	})
+}
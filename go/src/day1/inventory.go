@@ -0,0 +1,255 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"os"
+	"parsing"
+	"sort"
+	"strings"
+)
+
+// elf is what a single Elf carries, as read from the inventory.
+// index counts the Elves from 1, lines are 1-based and inclusive.
+type elf struct {
+	index     int
+	firstLine int
+	lastLine  int
+	items     []int
+	total     int
+}
+
+// inventoryWarning points at suspicious but readable input
+type inventoryWarning struct {
+	line    int
+	message string
+}
+
+// parseInventory reads the inventory of every Elf.
+//
+// Lines that are not numbers are errors, while input that the puzzle
+// solutions would silently accept is reported as warnings: runs of blank
+// lines (which would create Elves without any food), leading blank lines,
+// surrounding whitespace and items without calories.
+func parseInventory(r io.Reader) ([]elf, []inventoryWarning, error) {
+	records := parsing.NewRecordScanner(r)
+	elves := []elf{}
+	warnings := []inventoryWarning{}
+	previousEmpty := false
+
+	for records.Scan() {
+		record := records.Record()
+		if len(record.Lines) == 0 {
+			if record.Line == 1 {
+				warnings = append(warnings, inventoryWarning{record.Line, "inventory starts with a blank line"})
+			} else if !previousEmpty {
+				warnings = append(warnings, inventoryWarning{record.Line, "consecutive blank lines, no Elf created"})
+			}
+			// a run of blank lines only gets a single warning
+			previousEmpty = record.Line > 1
+			continue
+		}
+		previousEmpty = false
+
+		current := elf{
+			index:     len(elves) + 1,
+			firstLine: record.Line,
+			lastLine:  record.Line + len(record.Lines) - 1,
+		}
+		for i, line := range record.Lines {
+			lineNumber := record.Line + i
+			trimmed := strings.TrimSpace(line)
+			if trimmed != line {
+				warnings = append(warnings, inventoryWarning{lineNumber, "whitespace around calories"})
+			}
+
+			var calories int
+			if err := parsing.Scan(trimmed, "{n}", &calories); err != nil {
+				return nil, nil, parsing.AtLine(err, lineNumber)
+			}
+			if calories < 0 {
+				return nil, nil, &parsing.Error{Line: lineNumber, Err: fmt.Errorf("negative calories %d", calories)}
+			}
+			if calories == 0 {
+				warnings = append(warnings, inventoryWarning{lineNumber, "item without calories"})
+			}
+
+			current.items = append(current.items, calories)
+			current.total += calories
+		}
+		elves = append(elves, current)
+	}
+	if err := records.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return elves, warnings, nil
+}
+
+// topElves returns the k Elves carrying the most calories.
+//
+// Elves tied with the k-th one are all included, so the result can be
+// longer than k. Ties are listed in inventory order.
+func topElves(elves []elf, k int) []elf {
+	sorted := make([]elf, len(elves))
+	copy(sorted, elves)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].total > sorted[j].total
+	})
+
+	if k >= len(sorted) {
+		return sorted
+	}
+	if k <= 0 {
+		return []elf{}
+	}
+	end := k
+	for end < len(sorted) && sorted[end].total == sorted[k-1].total {
+		end++
+	}
+	return sorted[:end]
+}
+
+func sortedTotals(elves []elf) []int {
+	totals := make([]int, len(elves))
+	for i, e := range elves {
+		totals[i] = e.total
+	}
+	sort.Ints(totals)
+	return totals
+}
+
+func mean(values []int) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0
+	for _, v := range values {
+		sum += v
+	}
+	return float64(sum) / float64(len(values))
+}
+
+// median expects sorted values
+func median(values []int) float64 {
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+	if n%2 == 1 {
+		return float64(values[n/2])
+	}
+	return float64(values[n/2-1]+values[n/2]) / 2
+}
+
+// percentile uses the nearest-rank method and expects sorted values
+func percentile(values []int, p float64) int {
+	if len(values) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(values))))
+	if rank < 1 {
+		rank = 1
+	}
+	return values[rank-1]
+}
+
+// histogram draws one bar per bucket of equal width between
+// the smallest and the largest value, values must be sorted
+func histogram(values []int, buckets int, width int) string {
+	if len(values) == 0 {
+		return ""
+	}
+	low, high := values[0], values[len(values)-1]
+	size := (high - low + buckets) / buckets
+	counts := make([]int, buckets)
+	for _, v := range values {
+		counts[(v-low)/size]++
+	}
+
+	most := 0
+	for _, c := range counts {
+		if c > most {
+			most = c
+		}
+	}
+
+	var b strings.Builder
+	for i, c := range counts {
+		bar := c * width / most
+		if c > 0 && bar == 0 {
+			bar = 1
+		}
+		from := low + i*size
+		fmt.Fprintf(&b, "%6d-%-6d | %-*s %d\n", from, from+size-1, width, strings.Repeat("#", bar), c)
+	}
+	return b.String()
+}
+
+func printReport(elves []elf, warnings []inventoryWarning, k int) {
+	for _, w := range warnings {
+		fmt.Printf("Warning, line %d: %s\n", w.line, w.message)
+	}
+	if len(elves) == 0 {
+		fmt.Println("No Elves in the inventory")
+		return
+	}
+
+	top := topElves(elves, k)
+	fmt.Printf("Top %d Elves:\n", k)
+	for rank, e := range top {
+		marker := ""
+		if rank >= k {
+			marker = " (tied)"
+		}
+		fmt.Printf("%3d. Elf %d, lines %d-%d, %d items, %d calories%s\n",
+			rank+1, e.index, e.firstLine, e.lastLine, len(e.items), e.total, marker)
+	}
+	if len(top) > k {
+		tied := 0
+		for _, e := range top {
+			if e.total == top[k-1].total {
+				tied++
+			}
+		}
+		fmt.Printf("%d Elves tie at the boundary with %d calories\n", tied, top[k-1].total)
+	}
+
+	totals := sortedTotals(elves)
+	fmt.Println()
+	fmt.Println("Elves:", len(elves))
+	fmt.Printf("Mean: %.1f\n", mean(totals))
+	fmt.Printf("Median: %.1f\n", median(totals))
+	for _, p := range []float64{10, 25, 75, 90, 99} {
+		fmt.Printf("P%.0f: %d\n", p, percentile(totals, p))
+	}
+
+	fmt.Println()
+	fmt.Println("Calories per Elf:")
+	fmt.Print(histogram(totals, 10, 40))
+
+	itemCounts := make([]int, len(elves))
+	for i, e := range elves {
+		itemCounts[i] = len(e.items)
+	}
+	sort.Ints(itemCounts)
+	fmt.Println()
+	fmt.Println("Items per Elf:")
+	fmt.Print(histogram(itemCounts, itemCounts[len(itemCounts)-1]-itemCounts[0]+1, 40))
+}
+
+func inventoryReport(filePath string, k int) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	elves, warnings, err := parseInventory(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	printReport(elves, warnings, k)
+}
@@ -74,12 +74,14 @@ Find the top three Elves carrying the most Calories. How many Calories are those
 package main
 
 import (
-	"bufio"
 	"container/heap"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
-	"strconv"
+	"parsing"
+	"runtime"
 )
 
 // Heap implementation taken from the docs & modified
@@ -110,25 +112,33 @@ func updateMaxCalories(calories int, maxCalories *int) {
 	}
 }
 
-func getMaxCalories(file *os.File) int {
-	scanner := bufio.NewScanner(file)
-	var currentCalories int
+// elfCalories adds up the calories of the items of an Elf
+func elfCalories(record parsing.Record) (int, error) {
+	total := 0
+	for i, line := range record.Lines {
+		var calories int
+		if err := parsing.Scan(line, "{n}", &calories); err != nil {
+			return 0, parsing.AtLine(err, record.Line+i)
+		}
+		total += calories
+	}
+	return total, nil
+}
+
+func getMaxCalories(r io.Reader) (int, error) {
+	records := parsing.NewRecordScanner(r)
 	var maxCalories int
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			updateMaxCalories(currentCalories, &maxCalories)
-			currentCalories = 0
-		} else {
-			calories, err := strconv.Atoi(line)
-			if err != nil {
-				log.Fatal(err)
-			}
-			currentCalories += calories
+	for records.Scan() {
+		calories, err := elfCalories(records.Record())
+		if err != nil {
+			return 0, err
 		}
+		updateMaxCalories(calories, &maxCalories)
+	}
+	if err := records.Err(); err != nil {
+		return 0, err
 	}
-	updateMaxCalories(currentCalories, &maxCalories)
-	return maxCalories
+	return maxCalories, nil
 }
 
 func partOne(filePath string) {
@@ -137,44 +147,43 @@ func partOne(filePath string) {
 		log.Fatal(err)
 	}
 	defer file.Close()
-	maxCalories := getMaxCalories(file)
+	maxCalories, err := getMaxCalories(file)
+	if err != nil {
+		log.Fatal(err)
+	}
 	fmt.Println("Max calories:", maxCalories)
 }
 
+// getTopKSum adds up the k biggest values, or all of them
+// when there are fewer than k
 func getTopKSum(h *IntMaxHeap, k int) int {
 	var sum int = 0
-	for i := 0; i < k; i++ {
+	for i := 0; i < k && h.Len() > 0; i++ {
 		sum += heap.Pop(h).(int)
 	}
 	return sum
 }
 
-func topKCaloriesSum(file *os.File, k int) int {
-	scanner := bufio.NewScanner(file)
+func topKCaloriesSum(r io.Reader, k int) (int, error) {
+	records := parsing.NewRecordScanner(r)
 
 	// Use a max heap to keep track of the top K calories
-	var currentCalories int = 0
 	h := &IntMaxHeap{}
 	heap.Init(h)
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			heap.Push(h, currentCalories)
-			currentCalories = 0
-		} else {
-			calories, err := strconv.Atoi(line)
-			if err != nil {
-				log.Fatal(err)
-			}
-			currentCalories += calories
+	for records.Scan() {
+		calories, err := elfCalories(records.Record())
+		if err != nil {
+			return 0, err
 		}
+		heap.Push(h, calories)
+	}
+	if err := records.Err(); err != nil {
+		return 0, err
 	}
-
-	heap.Push(h, currentCalories)
 
 	topKSum := getTopKSum(h, k)
-	return topKSum
+	return topKSum, nil
 }
 
 func partTwo(filePath string, k int) {
@@ -184,19 +193,59 @@ func partTwo(filePath string, k int) {
 	}
 	defer file.Close()
 
-	topKCaloriesSum := topKCaloriesSum(file, k)
+	topKCaloriesSum, err := topKCaloriesSum(file, k)
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	fmt.Println("Top K calories:", topKCaloriesSum)
 }
 
 func main() {
+	input := flag.String("input", "../../input/day1.txt", "inventory file")
+	report := flag.Bool("report", false, "print a per-Elf inventory report instead")
+	k := flag.Int("k", 3, "number of Elves in the report or the parallel top K")
+	parallel := flag.Bool("parallel", false, "sum the top K calories reading chunks of the file concurrently instead")
+	workers := flag.Int("workers", runtime.NumCPU(), "number of goroutines for the parallel top K")
+	balance := flag.Bool("balance", false, "plan a fair redistribution of the food instead")
+	objective := flag.String("objective", "max", "what the redistribution minimises (max or variance)")
+	maxMoves := flag.Int("max-moves", -1, "maximum number of items to move, -1 for no limit")
+	flag.Parse()
+
+	if *k < 1 {
+		log.Fatal("k must be positive")
+	}
+	if *workers < 1 {
+		log.Fatal("Need at least one worker")
+	}
+
+	if *parallel {
+		partTwoParallel(*input, *k, *workers)
+		return
+	}
+	if *report {
+		inventoryReport(*input, *k)
+		return
+	}
+	if *balance {
+		switch *objective {
+		case "max":
+			balanceReport(*input, minimiseMax, *maxMoves)
+		case "variance":
+			balanceReport(*input, minimiseVariance, *maxMoves)
+		default:
+			log.Fatal("Invalid objective")
+		}
+		return
+	}
+
 	fmt.Println("Part One ----------------------------")
-	partOne("../../input/day1.txt")
+	partOne(*input)
 	fmt.Println("-------------------------------------")
 	fmt.Println("Part 1, but with heaps --------------")
-	partTwo("../../input/day1.txt", 1)
+	partTwo(*input, 1)
 	fmt.Println("-------------------------------------")
 	fmt.Println("Part Two ----------------------------")
-	partTwo("../../input/day1.txt", 3)
+	partTwo(*input, 3)
 	fmt.Println("-------------------------------------")
 }
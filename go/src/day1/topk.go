@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"container/heap"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"parsing"
+	"sync"
+)
+
+// IntMinHeap is the mirror image of IntMaxHeap.
+// Keeping the smallest of the top K on top lets us drop it in O(log K)
+// whenever a bigger value shows up, so the heap never grows past K.
+type IntMinHeap []int
+
+func (h IntMinHeap) Len() int           { return len(h) }
+func (h IntMinHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h IntMinHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *IntMinHeap) Push(x any) {
+	*h = append(*h, x.(int))
+}
+
+func (h *IntMinHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[0 : n-1]
+	return x
+}
+
+// pushTopK adds a value to a heap holding at most k values
+func pushTopK(h *IntMinHeap, k int, value int) {
+	if h.Len() < k {
+		heap.Push(h, value)
+	} else if k > 0 && value > (*h)[0] {
+		(*h)[0] = value
+		heap.Fix(h, 0)
+	}
+}
+
+func heapSum(h IntMinHeap) int {
+	sum := 0
+	for _, v := range h {
+		sum += v
+	}
+	return sum
+}
+
+// topKCalories streams the inventory and keeps only the k biggest totals
+func topKCalories(r io.Reader, k int) (IntMinHeap, error) {
+	records := parsing.NewRecordScanner(r)
+	h := &IntMinHeap{}
+
+	for records.Scan() {
+		record := records.Record()
+		// runs of blank lines give records without any Elf
+		if len(record.Lines) == 0 {
+			continue
+		}
+		calories, err := elfCalories(record)
+		if err != nil {
+			return nil, err
+		}
+		pushTopK(h, k, calories)
+	}
+	if err := records.Err(); err != nil {
+		return nil, err
+	}
+
+	return *h, nil
+}
+
+func boundedTopKSum(r io.Reader, k int) (int, error) {
+	h, err := topKCalories(r, k)
+	if err != nil {
+		return 0, err
+	}
+	return heapSum(h), nil
+}
+
+// nextElfStart returns the offset of the first Elf starting at or after
+// offset, which is right after the next blank line. Splitting the file
+// there guarantees that no Elf is shared between two chunks.
+func nextElfStart(file io.ReaderAt, offset int64, size int64) (int64, error) {
+	if offset <= 0 {
+		return 0, nil
+	}
+
+	// start one byte early so a blank line right at offset is found too
+	pos := offset - 1
+	buf := make([]byte, 64*1024)
+	prev := byte(0)
+	for pos < size {
+		n, err := file.ReadAt(buf, pos)
+		if n == 0 && err != nil {
+			if err == io.EOF {
+				break
+			}
+			return 0, err
+		}
+
+		if prev == '\n' && buf[0] == '\n' {
+			return pos + 1, nil
+		}
+		if i := bytes.Index(buf[:n], []byte("\n\n")); i >= 0 {
+			return pos + int64(i) + 2, nil
+		}
+		prev = buf[n-1]
+		pos += int64(n)
+	}
+	return size, nil
+}
+
+// chunkOffsets splits a file in roughly equal chunks at Elf boundaries
+func chunkOffsets(file io.ReaderAt, size int64, chunks int) ([]int64, error) {
+	offsets := []int64{0}
+	for i := 1; i < chunks; i++ {
+		start, err := nextElfStart(file, size*int64(i)/int64(chunks), size)
+		if err != nil {
+			return nil, err
+		}
+		if start > offsets[len(offsets)-1] && start < size {
+			offsets = append(offsets, start)
+		}
+	}
+	return append(offsets, size), nil
+}
+
+// linesBefore counts the lines of the file that end before the offset
+func linesBefore(file io.ReaderAt, offset int64) (int, error) {
+	lines := 0
+	buf := make([]byte, 64*1024)
+	for pos := int64(0); pos < offset; {
+		n, err := file.ReadAt(buf[:min(int64(len(buf)), offset-pos)], pos)
+		lines += bytes.Count(buf[:n], []byte{'\n'})
+		pos += int64(n)
+		if err != nil && err != io.EOF {
+			return 0, err
+		}
+		if n == 0 {
+			break
+		}
+	}
+	return lines, nil
+}
+
+// parallelTopKSum computes the top k of every chunk of the file in its
+// own goroutine and merges them, the top k overall is always among them
+func parallelTopKSum(filePath string, k int, workers int) (int, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+	offsets, err := chunkOffsets(file, info.Size(), workers)
+	if err != nil {
+		return 0, err
+	}
+
+	chunks := len(offsets) - 1
+	results := make([]IntMinHeap, chunks)
+	errs := make([]error, chunks)
+	var wg sync.WaitGroup
+	for i := 0; i < chunks; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			section := io.NewSectionReader(file, offsets[i], offsets[i+1]-offsets[i])
+			results[i], errs[i] = topKCalories(section, k)
+		}(i)
+	}
+	wg.Wait()
+
+	merged := &IntMinHeap{}
+	for i := 0; i < chunks; i++ {
+		if errs[i] != nil {
+			// the chunk counted its lines from its own start
+			var e *parsing.Error
+			if errors.As(errs[i], &e) && e.Line > 0 {
+				before, err := linesBefore(file, offsets[i])
+				if err != nil {
+					return 0, err
+				}
+				e.Line += before
+			}
+			return 0, errs[i]
+		}
+		for _, v := range results[i] {
+			pushTopK(merged, k, v)
+		}
+	}
+	return heapSum(*merged), nil
+}
+
+func partTwoParallel(filePath string, k int, workers int) {
+	sum, err := parallelTopKSum(filePath, k, workers)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Top K calories (%d workers): %d\n", workers, sum)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"io"
+	"os"
+	"runtime"
+	"testing"
+)
+
+const benchmarkInput = "../../input/day1.txt"
+
+// benchmarkFile runs a solution on the committed input, rewinding
+// the file before every run
+func benchmarkFile(b *testing.B, run func(r io.Reader) error) {
+	file, err := os.Open(benchmarkInput)
+	if err != nil {
+		b.Skip(err)
+	}
+	defer file.Close()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			b.Fatal(err)
+		}
+		if err := run(file); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGetMaxCalories(b *testing.B) {
+	benchmarkFile(b, func(r io.Reader) error {
+		_, err := getMaxCalories(r)
+		return err
+	})
+}
+
+func BenchmarkTopKCaloriesSum(b *testing.B) {
+	benchmarkFile(b, func(r io.Reader) error {
+		_, err := topKCaloriesSum(r, 3)
+		return err
+	})
+}
+
+func BenchmarkBoundedTopKSum(b *testing.B) {
+	benchmarkFile(b, func(r io.Reader) error {
+		_, err := boundedTopKSum(r, 3)
+		return err
+	})
+}
+
+func BenchmarkParallelTopKSum(b *testing.B) {
+	if _, err := os.Stat(benchmarkInput); err != nil {
+		b.Skip(err)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := parallelTopKSum(benchmarkInput, 3, runtime.NumCPU()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
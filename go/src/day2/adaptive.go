@@ -0,0 +1,221 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"math/rand"
+)
+
+// player picks its move every round knowing only the previous rounds
+type player interface {
+	name() string
+	choose() move
+	observe(opponent move, response move, o outcome)
+}
+
+// bestResponse is the move scoring the most against the predicted move
+func bestResponse(matrix payoffMatrix, predicted move) move {
+	best := move(0)
+	for m := range matrix.scores[predicted] {
+		if matrix.scores[predicted][m] > matrix.scores[predicted][best] {
+			best = move(m)
+		}
+	}
+	return best
+}
+
+// mostFrequent returns the most common move, the lowest one on ties
+func mostFrequent(counts []int) move {
+	best := move(0)
+	for m, c := range counts {
+		if c > counts[best] {
+			best = move(m)
+		}
+	}
+	return best
+}
+
+// frequencyPlayer expects the opponent to play its favourite move again
+type frequencyPlayer struct {
+	matrix payoffMatrix
+	counts []int
+}
+
+func newFrequencyPlayer(matrix payoffMatrix) *frequencyPlayer {
+	return &frequencyPlayer{matrix, make([]int, matrix.rules.moveCount())}
+}
+
+func (p *frequencyPlayer) name() string {
+	return "frequency"
+}
+
+func (p *frequencyPlayer) choose() move {
+	return bestResponse(p.matrix, mostFrequent(p.counts))
+}
+
+func (p *frequencyPlayer) observe(opponent move, response move, o outcome) {
+	p.counts[opponent]++
+}
+
+// markovPlayer predicts the opponent from what followed the same last k
+// moves before, and falls back to the overall frequency for new contexts
+type markovPlayer struct {
+	matrix   payoffMatrix
+	order    int
+	history  []move
+	counts   map[int][]int
+	fallback *frequencyPlayer
+}
+
+func newMarkovPlayer(matrix payoffMatrix, order int) *markovPlayer {
+	return &markovPlayer{
+		matrix:   matrix,
+		order:    order,
+		counts:   make(map[int][]int),
+		fallback: newFrequencyPlayer(matrix),
+	}
+}
+
+func (p *markovPlayer) name() string {
+	return fmt.Sprintf("markov (order %d)", p.order)
+}
+
+// context encodes the last k moves of the opponent as a single number
+func (p *markovPlayer) context() (int, bool) {
+	if len(p.history) < p.order {
+		return 0, false
+	}
+	key := 0
+	for _, m := range p.history[len(p.history)-p.order:] {
+		key = key*p.matrix.rules.moveCount() + int(m)
+	}
+	return key, true
+}
+
+func (p *markovPlayer) choose() move {
+	key, ok := p.context()
+	if !ok || p.counts[key] == nil {
+		return p.fallback.choose()
+	}
+	return bestResponse(p.matrix, mostFrequent(p.counts[key]))
+}
+
+func (p *markovPlayer) observe(opponent move, response move, o outcome) {
+	if key, ok := p.context(); ok {
+		if p.counts[key] == nil {
+			p.counts[key] = make([]int, p.matrix.rules.moveCount())
+		}
+		p.counts[key][opponent]++
+	}
+	p.history = append(p.history, opponent)
+	p.fallback.observe(opponent, response, o)
+}
+
+// winStayLoseShiftPlayer keeps a winning move, and otherwise switches
+// to the move that would have beaten the opponent's last move
+type winStayLoseShiftPlayer struct {
+	matrix payoffMatrix
+	next   move
+}
+
+func newWinStayLoseShiftPlayer(matrix payoffMatrix) *winStayLoseShiftPlayer {
+	return &winStayLoseShiftPlayer{matrix: matrix}
+}
+
+func (p *winStayLoseShiftPlayer) name() string {
+	return "win-stay/lose-shift"
+}
+
+func (p *winStayLoseShiftPlayer) choose() move {
+	return p.next
+}
+
+func (p *winStayLoseShiftPlayer) observe(opponent move, response move, o outcome) {
+	if o == win {
+		p.next = response
+	} else {
+		p.next = bestResponse(p.matrix, opponent)
+	}
+}
+
+func adaptivePlayers(matrix payoffMatrix, order int) []player {
+	return []player{
+		newFrequencyPlayer(matrix),
+		newMarkovPlayer(matrix, order),
+		newWinStayLoseShiftPlayer(matrix),
+	}
+}
+
+// playOnline lets a player face the opponent moves one round at a time
+func playOnline(opponents []move, p player, matrix payoffMatrix) (int, int) {
+	score, wins := 0, 0
+	for _, opponent := range opponents {
+		response := p.choose()
+		o := matrix.outcomes[opponent][response]
+		score += matrix.scores[opponent][response]
+		if o == win {
+			wins++
+		}
+		p.observe(opponent, response, o)
+	}
+	return score, wins
+}
+
+func opponentMoves(rounds []round) []move {
+	moves := make([]move, len(rounds))
+	for i, r := range rounds {
+		moves[i] = r.opponent
+	}
+	return moves
+}
+
+func printAdaptive(rounds []round, matrix payoffMatrix, order int) {
+	partOneScore, err := getTotalScore(rounds, asMove, matrix)
+	if err != nil {
+		log.Fatal(err)
+	}
+	partTwoScore, err := getTotalScore(rounds, asOutcome, matrix)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Printf("%-22s %6d\n", "guide (part one)", partOneScore)
+	fmt.Printf("%-22s %6d\n", "guide (part two)", partTwoScore)
+	opponents := opponentMoves(rounds)
+	for _, p := range adaptivePlayers(matrix, order) {
+		score, wins := playOnline(opponents, p, matrix)
+		fmt.Printf("%-22s %6d (%d wins)\n", p.name(), score, wins)
+	}
+}
+
+// monteCarlo replays the adaptive players against opponents picking
+// uniformly random moves for as many rounds as the guide has
+func monteCarlo(rounds int, matrix payoffMatrix, order int, trials int, seed int64) {
+	rng := rand.New(rand.NewSource(seed))
+	names := []string{}
+	totals := []int{}
+	wins := []int{}
+
+	for t := 0; t < trials; t++ {
+		opponents := make([]move, rounds)
+		for i := range opponents {
+			opponents[i] = move(rng.Intn(matrix.rules.moveCount()))
+		}
+		for i, p := range adaptivePlayers(matrix, order) {
+			if t == 0 {
+				names = append(names, p.name())
+				totals = append(totals, 0)
+				wins = append(wins, 0)
+			}
+			score, won := playOnline(opponents, p, matrix)
+			totals[i] += score
+			wins[i] += won
+		}
+	}
+
+	fmt.Printf("%d random opponents, %d rounds each, seed %d\n", trials, rounds, seed)
+	for i, name := range names {
+		fmt.Printf("%-22s mean score %.1f, mean wins %.1f\n",
+			name, float64(totals[i])/float64(trials), float64(wins[i])/float64(trials))
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+// the moves of the default rules
+const (
+	rock move = iota
+	paper
+	scissors
+)
+
+func TestBestResponse(t *testing.T) {
+	matrix := exampleMatrix(t)
+	for predicted, want := range map[move]move{rock: paper, paper: scissors, scissors: rock} {
+		if got := bestResponse(matrix, predicted); got != want {
+			t.Errorf("best response to %d is %d, expected %d", predicted, got, want)
+		}
+	}
+	if got := mostFrequent([]int{2, 3, 3}); got != paper {
+		t.Errorf("most frequent of a tie is %d, expected the lowest move %d", got, paper)
+	}
+}
+
+func repeat(pattern []move, n int) []move {
+	moves := make([]move, n)
+	for i := range moves {
+		moves[i] = pattern[i%len(pattern)]
+	}
+	return moves
+}
+
+func TestPlayOnline(t *testing.T) {
+	matrix := exampleMatrix(t)
+	tests := []struct {
+		name      string
+		player    player
+		opponents []move
+		score     int
+		wins      int
+	}{
+		// paper beats the favourite rock from the very first round
+		{"frequency", newFrequencyPlayer(matrix), repeat([]move{rock}, 10), 80, 10},
+		// rock to start with, which keeps winning against scissors
+		{"win-stay", newWinStayLoseShiftPlayer(matrix), repeat([]move{scissors}, 10), 70, 10},
+		// a loss, then the move beating the last one keeps winning
+		{"lose-shift", newWinStayLoseShiftPlayer(matrix), repeat([]move{paper}, 10), 1 + 9*9, 9},
+		// the cycle is learnt once every move has been followed once
+		{"markov", newMarkovPlayer(matrix, 1), repeat([]move{rock, paper, scissors}, 30), 231, 28},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, wins := playOnline(tt.opponents, tt.player, matrix)
+			if wins != tt.wins || score != tt.score {
+				t.Fatalf("%s: score %d with %d wins, expected %d with %d wins", tt.player.name(), score, wins, tt.score, tt.wins)
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// cipherMapping is one way of reading the second column of the guide,
+// either as the move to play or as the outcome to reach
+type cipherMapping struct {
+	kind   string
+	key    string
+	score  int
+	wins   int
+	draws  int
+	losses int
+	rounds int
+}
+
+func (c cipherMapping) winRate() float64 {
+	if c.rounds == 0 {
+		return 0
+	}
+	return 100 * float64(c.wins) / float64(c.rounds)
+}
+
+// permutations returns every ordering of 0..n-1 in lexicographic order
+func permutations(n int) [][]int {
+	result := [][]int{}
+	current := make([]int, 0, n)
+	used := make([]bool, n)
+
+	var build func()
+	build = func() {
+		if len(current) == n {
+			result = append(result, append([]int{}, current...))
+			return
+		}
+		for i := 0; i < n; i++ {
+			if used[i] {
+				continue
+			}
+			used[i] = true
+			current = append(current, i)
+			build()
+			current = current[:len(current)-1]
+			used[i] = false
+		}
+	}
+	build()
+
+	return result
+}
+
+func sortedLetters[T any](letters map[byte]T) []byte {
+	sorted := make([]byte, 0, len(letters))
+	for letter := range letters {
+		sorted = append(sorted, letter)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return sorted
+}
+
+// evaluateCipher plays the whole guide with the given decoder.
+// It fails if the decoder does not know a letter of the guide.
+func evaluateCipher(rounds []round, matrix payoffMatrix, decode func(byte, move) (move, bool)) (cipherMapping, bool) {
+	result := cipherMapping{rounds: len(rounds)}
+	for _, r := range rounds {
+		response, ok := decode(r.column, r.opponent)
+		if !ok {
+			return result, false
+		}
+		result.score += matrix.scores[r.opponent][response]
+		switch matrix.outcomes[r.opponent][response] {
+		case win:
+			result.wins++
+		case draw:
+			result.draws++
+		case loss:
+			result.losses++
+		}
+	}
+	return result, true
+}
+
+// inferCiphers scores every bijection of the second column letters to
+// moves and to outcomes against the guide, best score first
+func inferCiphers(rounds []round, matrix payoffMatrix) []cipherMapping {
+	rules := matrix.rules
+	mappings := []cipherMapping{}
+
+	letters := sortedLetters(rules.responseLetters)
+	for _, perm := range permutations(len(letters)) {
+		cipher := make(map[byte]move)
+		key := make([]string, len(letters))
+		for i, letter := range letters {
+			cipher[letter] = move(perm[i])
+			key[i] = fmt.Sprintf("%c=%s", letter, rules.moveName(move(perm[i])))
+		}
+
+		mapping, ok := evaluateCipher(rounds, matrix, func(c byte, opponent move) (move, bool) {
+			m, ok := cipher[c]
+			return m, ok
+		})
+		if ok {
+			mapping.kind = "move"
+			mapping.key = strings.Join(key, " ")
+			mappings = append(mappings, mapping)
+		}
+	}
+
+	letters = sortedLetters(rules.outcomeLetters)
+	for _, perm := range permutations(len(letters)) {
+		cipher := make(map[byte]outcome)
+		key := make([]string, len(letters))
+		for i, letter := range letters {
+			cipher[letter] = outcome(perm[i])
+			key[i] = fmt.Sprintf("%c=%s", letter, outcome(perm[i]))
+		}
+
+		mapping, ok := evaluateCipher(rounds, matrix, func(c byte, opponent move) (move, bool) {
+			o, ok := cipher[c]
+			return matrix.responses[opponent][o], ok
+		})
+		if ok {
+			mapping.kind = "outcome"
+			mapping.key = strings.Join(key, " ")
+			mappings = append(mappings, mapping)
+		}
+	}
+
+	sort.SliceStable(mappings, func(i, j int) bool {
+		return mappings[i].score > mappings[j].score
+	})
+	return mappings
+}
+
+// printCiphers prints the ranked table and answers which mapping scores
+// best while winning at most maxWinRate percent of the rounds, since
+// winning every time would be suspicious
+func printCiphers(mappings []cipherMapping, maxWinRate float64) {
+	fmt.Printf("%-8s %-44s %6s %5s %5s %6s %6s\n", "Kind", "Mapping", "Score", "Wins", "Draws", "Losses", "Win%")
+	for _, m := range mappings {
+		fmt.Printf("%-8s %-44s %6d %5d %5d %6d %5.1f%%\n", m.kind, m.key, m.score, m.wins, m.draws, m.losses, m.winRate())
+	}
+
+	fmt.Println()
+	for _, m := range mappings {
+		if m.winRate() <= maxWinRate {
+			fmt.Printf("Best mapping winning at most %.1f%% of rounds: %s %s with %d points\n", maxWinRate, m.kind, m.key, m.score)
+			return
+		}
+	}
+	fmt.Printf("No mapping wins at most %.1f%% of rounds\n", maxWinRate)
+}
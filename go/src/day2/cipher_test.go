@@ -0,0 +1,57 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestPermutations(t *testing.T) {
+	want := [][]int{{0, 1, 2}, {0, 2, 1}, {1, 0, 2}, {1, 2, 0}, {2, 0, 1}, {2, 1, 0}}
+	got := permutations(3)
+	if !slices.EqualFunc(got, want, slices.Equal[[]int]) {
+		t.Fatalf("permutations of 3: %v", got)
+	}
+	if n := len(permutations(5)); n != 120 {
+		t.Fatalf("%d permutations of 5", n)
+	}
+}
+
+// TestInferCiphers checks that the puzzle's readings of the example are
+// among the mappings with their scores, best score first
+func TestInferCiphers(t *testing.T) {
+	matrix := exampleMatrix(t)
+	rounds, err := parseRounds(strings.NewReader(example), matrix.rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mappings := inferCiphers(rounds, matrix)
+	if len(mappings) != 12 {
+		t.Fatalf("%d mappings, expected 6 for the moves and 6 for the outcomes", len(mappings))
+	}
+	found := map[string]cipherMapping{}
+	for i, m := range mappings {
+		if i > 0 && m.score > mappings[i-1].score {
+			t.Fatalf("mapping %d scores %d, more than the one before", i+1, m.score)
+		}
+		if m.wins+m.draws+m.losses != m.rounds || m.rounds != len(rounds) {
+			t.Fatalf("%s %s: %d wins, %d draws and %d losses in %d rounds", m.kind, m.key, m.wins, m.draws, m.losses, m.rounds)
+		}
+		found[m.kind+" "+m.key] = m
+	}
+
+	tests := []struct {
+		mapping string
+		score   int
+	}{
+		{"move X=rock Y=paper Z=scissors", 15},
+		{"outcome X=loss Y=draw Z=win", 12},
+	}
+	for _, tt := range tests {
+		m, ok := found[tt.mapping]
+		if !ok || m.score != tt.score {
+			t.Errorf("%s: found %v with %d points, expected %d", tt.mapping, ok, m.score, tt.score)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"os"
+	"parsing"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const example = `A Y
+B X
+C Z
+`
+
+// exampleScores are the puzzle's answers for the example with the
+// default rules, playing the second column as a move and as an outcome
+var exampleScores = map[string]int{"move": 15, "outcome": 12}
+
+func addInputs(f *testing.F) {
+	f.Add([]byte(example))
+	if input, err := os.ReadFile("../../input/day2.txt"); err == nil {
+		f.Add(input)
+	}
+}
+
+// FuzzStrategyGuide reads the guide with every preset and plays both
+// parts. A bad line must give a parsing error and a good guide a score
+// between the worst and the best possible rounds.
+func FuzzStrategyGuide(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		for name := range presets {
+			rules, err := loadPreset(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			matrix := getScoreMap(rules)
+
+			rounds, err := parseRounds(bytes.NewReader(data), rules)
+			if err != nil {
+				var parseErr *parsing.Error
+				if !errors.As(err, &parseErr) && !errors.Is(err, bufio.ErrTooLong) {
+					t.Fatalf("%s: untyped error %v", name, err)
+				}
+				continue
+			}
+
+			lowest, highest := matrix.scores[0][0], matrix.scores[0][0]
+			for _, scores := range matrix.scores {
+				lowest = min(lowest, slices.Min(scores))
+				highest = max(highest, slices.Max(scores))
+			}
+			for column, decode := range map[string]decoder{"move": asMove, "outcome": asOutcome} {
+				score, err := getTotalScore(rounds, decode, matrix)
+				if err != nil {
+					continue
+				}
+				if score < lowest*len(rounds) || score > highest*len(rounds) {
+					t.Fatalf("%s: score %d for %d rounds", name, score, len(rounds))
+				}
+				if string(data) == example && name == defaultPreset && score != exampleScores[column] {
+					t.Fatalf("example as %s: score %d, expected %d", column, score, exampleScores[column])
+				}
+			}
+		}
+	})
+}
+
+// FuzzRuleSet loads any rule set, once accepted every opponent move must
+// have a response for each outcome
+func FuzzRuleSet(f *testing.F) {
+	for _, path := range presets {
+		data, err := presetFiles.ReadFile(path)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		rules, err := parseRuleSet(data)
+		if err != nil {
+			return
+		}
+
+		matrix := getScoreMap(rules)
+		for opponent := move(0); int(opponent) < rules.moveCount(); opponent++ {
+			for o := loss; o < outcomeCount; o++ {
+				response := matrix.responses[opponent][o]
+				if got := getOutcome(rules, opponent, response); got != o {
+					t.Fatalf("%s against %s is a %s, expected a %s", rules.moveName(response), rules.moveName(opponent), got, o)
+				}
+			}
+		}
+		if _, err := parseRounds(strings.NewReader(example), rules); err != nil {
+			var parseErr *parsing.Error
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("untyped error %v", err)
+			}
+		}
+	})
+}
@@ -1,5 +1,4 @@
 /*
-
 --- Day 2: Rock Paper Scissors ---
 
 The Elves begin to set up camp on the beach. To decide whose tent gets to be closest to the
@@ -34,11 +33,11 @@ C Z
 
 This strategy guide predicts and recommends the following:
 
-    In the first round, your opponent will choose Rock (A), and you should choose Paper (Y). This ends in
-		a win for you with a score of 8 (2 because you chose Paper + 6 because you won).
-    In the second round, your opponent will choose Paper (B), and you should choose Rock (X). This ends in
-		a loss for you with a score of 1 (1 + 0).
-    The third round is a draw with both players choosing Scissors, giving you a score of 3 + 3 = 6.
+	    In the first round, your opponent will choose Rock (A), and you should choose Paper (Y). This ends in
+			a win for you with a score of 8 (2 because you chose Paper + 6 because you won).
+	    In the second round, your opponent will choose Paper (B), and you should choose Rock (X). This ends in
+			a loss for you with a score of 1 (1 + 0).
+	    The third round is a draw with both players choosing Scissors, giving you a score of 3 + 3 = 6.
 
 In this example, if you were to follow the strategy guide, you would get a total score of 15 (8 + 1 + 6).
 
@@ -53,160 +52,295 @@ in a draw, and Z means you need to win. Good luck!"
 The total score is still calculated in the same way, but now you need to figure out what shape
 to choose so the round ends as indicated. The example above now goes like this:
 
-    In the first round, your opponent will choose Rock (A), and you need the round to end
-		in a draw (Y), so you also choose Rock. This gives you a score of 1 + 3 = 4.
-    In the second round, your opponent will choose Paper (B), and you choose Rock so you
-		lose (X) with a score of 1 + 0 = 1.
-    In the third round, you will defeat your opponent's Scissors with Rock for a score of 1 + 6 = 7.
+	    In the first round, your opponent will choose Rock (A), and you need the round to end
+			in a draw (Y), so you also choose Rock. This gives you a score of 1 + 3 = 4.
+	    In the second round, your opponent will choose Paper (B), and you choose Rock so you
+			lose (X) with a score of 1 + 0 = 1.
+	    In the third round, you will defeat your opponent's Scissors with Rock for a score of 1 + 6 = 7.
 
 Now that you're correctly decrypting the ultra top secret strategy guide, you would get a total score of 12.
 
 Following the Elf's instructions for the second column, what would your total score be if
 everything goes exactly according to your strategy guide?
-
-
 */
 
 package main
 
 import (
 	"bufio"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"parsing"
+	"strings"
 )
 
-type pair [2]string
-type strStrInt map[string]map[string]int
+// move indexes the moves of the rule set being played
+type move int
+type outcome int
 
-func letters2Names(input string) string {
-	if input == "A" || input == "X" {
-		return "rock"
-	} else if input == "B" || input == "Y" {
-		return "paper"
-	} else if input == "C" || input == "Z" {
-		return "scissors"
-	} else {
-		return "INVALID"
+const (
+	loss outcome = iota
+	draw
+	win
+	outcomeCount
+)
+
+func (o outcome) String() string {
+	switch o {
+	case loss:
+		return "loss"
+	case draw:
+		return "draw"
+	case win:
+		return "win"
 	}
+	return fmt.Sprintf("outcome(%d)", int(o))
 }
 
-func xyz2score(s string) int {
-	if s == "X" {
-		return 0
-	} else if s == "Y" {
-		return 3
-	} else if s == "Z" {
-		return 6
-	} else {
-		return -1
+// round is a line of the strategy guide.
+// What the second column means depends on the part of the puzzle.
+type round struct {
+	opponent move
+	column   byte
+}
+
+// payoffMatrix holds everything there is to know about a round,
+// computed once from the rules of the game
+type payoffMatrix struct {
+	rules     *ruleSet
+	outcomes  [][]outcome          // by opponent move and response
+	responses [][outcomeCount]move // by opponent move and wanted outcome
+	scores    [][]int              // by opponent move and response
+}
+
+// decoder turns the second column of a round into the move to play
+type decoder func(round round, matrix payoffMatrix) (move, error)
+
+func getOutcome(rules *ruleSet, opponent move, response move) outcome {
+	if rules.beats[response][opponent] {
+		return win
+	} else if response == opponent {
+		return draw
+	}
+	return loss
+}
+
+func getMoveScore(rules *ruleSet, m move) int {
+	return rules.Moves[m].Score
+}
+
+func getOutcomeScore(rules *ruleSet, o outcome) int {
+	return rules.outcomeScores[o]
+}
+
+// getScoreMap precomputes the payoff matrix of a rule set.
+// When several moves give the wanted outcome, as in games with more than
+// three moves, the response with the best score is the one played.
+func getScoreMap(rules *ruleSet) payoffMatrix {
+	n := rules.moveCount()
+	matrix := payoffMatrix{
+		rules:     rules,
+		outcomes:  make([][]outcome, n),
+		responses: make([][outcomeCount]move, n),
+		scores:    make([][]int, n),
+	}
+
+	for opponent := move(0); int(opponent) < n; opponent++ {
+		matrix.outcomes[opponent] = make([]outcome, n)
+		matrix.scores[opponent] = make([]int, n)
+		found := [outcomeCount]bool{}
+		for response := move(0); int(response) < n; response++ {
+			o := getOutcome(rules, opponent, response)
+			score := getOutcomeScore(rules, o) + getMoveScore(rules, response)
+			matrix.outcomes[opponent][response] = o
+			matrix.scores[opponent][response] = score
+
+			best := matrix.responses[opponent][o]
+			if !found[o] || score > matrix.scores[opponent][best] {
+				matrix.responses[opponent][o] = response
+				found[o] = true
+			}
+		}
 	}
+	return matrix
 }
 
-func defeatingMove(move string) string {
-	switch move {
-	case "rock":
-		return "paper"
-	case "paper":
-		return "scissors"
-	case "scissors":
-		return "rock"
+func decodeOpponent(rules *ruleSet, c byte) (move, error) {
+	m, ok := rules.opponentLetters[c]
+	if !ok {
+		return 0, fmt.Errorf("invalid opponent move %q", c)
 	}
-	return "INVALID"
+	return m, nil
 }
 
-func getScore(round pair) int {
-	if round[1] == defeatingMove(round[0]) {
-		return 6
-	} else if round[0] == round[1] {
-		return 3
-	} else {
-		return 0
+func decodeResponse(rules *ruleSet, c byte) (move, error) {
+	m, ok := rules.responseLetters[c]
+	if !ok {
+		return 0, fmt.Errorf("invalid response %q", c)
 	}
+	return m, nil
 }
 
-func getScoreMap() strStrInt {
-	scoreMap := make(strStrInt)
-	moves := []string{"rock", "paper", "scissors"}
-	for _, move1 := range moves {
-		scoreMap[move1] = make(map[string]int)
-		for _, move2 := range moves {
-			scoreMap[move1][move2] = getScore(pair{move1, move2})
-		}
+func decodeOutcome(rules *ruleSet, c byte) (outcome, error) {
+	o, ok := rules.outcomeLetters[c]
+	if !ok {
+		return 0, fmt.Errorf("invalid outcome %q", c)
 	}
-	return scoreMap
+	return o, nil
 }
 
-func getMoveScore(move string) int {
-	switch move {
-	case "rock":
-		return 1
-	case "paper":
-		return 2
-	case "scissors":
-		return 3
+// asMove is the first guess: the second column is the move to play
+func asMove(r round, matrix payoffMatrix) (move, error) {
+	return decodeResponse(matrix.rules, r.column)
+}
+
+// asOutcome is the Elf's explanation: the second column is how the round ends
+func asOutcome(r round, matrix payoffMatrix) (move, error) {
+	o, err := decodeOutcome(matrix.rules, r.column)
+	if err != nil {
+		return 0, err
 	}
-	return -1
+	return matrix.responses[r.opponent][o], nil
 }
 
-func getMoveFromScore(move1 string, score int, scoreMap strStrInt) string {
-	for move2, s := range scoreMap[move1] {
-		if s == score {
-			return move2
-		}
+func parseRound(line string, rules *ruleSet) (round, error) {
+	var first, second byte
+	if err := parsing.Scan(line, "{c} {c}", &first, &second); err != nil {
+		return round{}, err
 	}
-	return "INVALID"
+	opponent, err := decodeOpponent(rules, first)
+	if err != nil {
+		return round{}, &parsing.Error{Column: 1, Err: err}
+	}
+	_, isResponse := rules.responseLetters[second]
+	_, isOutcome := rules.outcomeLetters[second]
+	if !isResponse && !isOutcome {
+		return round{}, &parsing.Error{Column: 3, Err: fmt.Errorf("invalid second column %q", second)}
+	}
+	return round{opponent, second}, nil
 }
 
-func partOne(rounds []pair, scoreMap strStrInt) {
+func getTotalScore(rounds []round, decode decoder, matrix payoffMatrix) (int, error) {
 	totalScore := 0
-	for _, round := range rounds {
-		move1 := letters2Names(round[0])
-		move2 := letters2Names(round[1])
-		totalScore += scoreMap[move1][move2] + getMoveScore(move2)
+	for i, r := range rounds {
+		response, err := decode(r, matrix)
+		if err != nil {
+			return 0, fmt.Errorf("round %d: %w", i+1, err)
+		}
+		totalScore += matrix.scores[r.opponent][response]
+	}
+	return totalScore, nil
+}
+
+func partOne(rounds []round, matrix payoffMatrix) {
+	totalScore, err := getTotalScore(rounds, asMove, matrix)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	fmt.Println("Part One:", totalScore)
 }
 
-func partTwo(rounds []pair, scoreMap strStrInt) {
-	totalScore := 0
-	for _, round := range rounds {
-		move1 := letters2Names(round[0])
-		score := xyz2score(round[1])
-		move2 := getMoveFromScore(move1, score, scoreMap)
-		totalScore += score + getMoveScore(move2)
+func partTwo(rounds []round, matrix payoffMatrix) {
+	totalScore, err := getTotalScore(rounds, asOutcome, matrix)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	fmt.Println("Part Two: ", totalScore)
 }
 
-func main() {
-	file, err := os.Open("../../input/day2.txt")
+func readRounds(filePath string, rules *ruleSet) ([]round, error) {
+	file, err := os.Open(filePath)
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
 	}
 	defer file.Close()
 
-	var player1Move string
-	var player2Move string
-	rounds := make([]pair, 0)
+	return parseRounds(file, rules)
+}
 
-	scanner := bufio.NewScanner(file)
+// parseRounds reads a round per line, skipping blank lines
+func parseRounds(r io.Reader, rules *ruleSet) ([]round, error) {
+	rounds := make([]round, 0)
+	lineNumber := 0
+	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
+		lineNumber++
 		line := scanner.Text()
 		if line == "" {
 			continue
 		}
 
-		player1Move = line[0:1]
-		player2Move = line[2:]
+		r, err := parseRound(line, rules)
+		if err != nil {
+			return nil, parsing.AtLine(err, lineNumber)
+		}
+		rounds = append(rounds, r)
+	}
+	return rounds, scanner.Err()
+}
+
+func main() {
+	input := flag.String("input", "../../input/day2.txt", "strategy guide")
+	preset := flag.String("preset", defaultPreset, "rule set to play (rps or rpsls)")
+	rulesFile := flag.String("rules", "", "load the rule set from a file instead of a preset")
+	infer := flag.Bool("infer", false, "rank every possible meaning of the second column")
+	maxWinRate := flag.Float64("max-win-rate", 100, "highest share of won rounds (in percent) that is not suspicious")
+	adaptive := flag.Bool("adaptive", false, "evaluate adaptive players against the opponent column")
+	order := flag.Int("order", 2, "number of past moves the Markov player looks at")
+	trials := flag.Int("monte-carlo", 0, "replay the adaptive players against this many random opponents")
+	seed := flag.Int64("seed", 1, "seed of the random opponents")
+	tournament := flag.String("tournament", "", "comma separated strategy guides playing a tournament")
+	format := flag.String("format", "round-robin", "tournament format (round-robin or bracket)")
+	concurrent := flag.Bool("concurrent", false, "play the tournament pairings concurrently")
+	flag.Parse()
+
+	if *order < 1 {
+		log.Fatal("order must be positive")
+	}
+
+	var rules *ruleSet
+	var err error
+	if *rulesFile != "" {
+		rules, err = loadRuleSet(*rulesFile)
+	} else {
+		rules, err = loadPreset(*preset)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *tournament != "" {
+		err := runTournament(strings.Split(*tournament, ","), *format, getScoreMap(rules), *concurrent)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	rounds, err := readRounds(*input, rules)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-		round := pair{player1Move, player2Move}
-		rounds = append(rounds, round)
+	scoreMap := getScoreMap(rules)
+	if *trials > 0 {
+		monteCarlo(len(rounds), scoreMap, *order, *trials, *seed)
+		return
+	}
+	if *adaptive {
+		printAdaptive(rounds, scoreMap, *order)
+		return
+	}
+	if *infer {
+		printCiphers(inferCiphers(rounds, scoreMap), *maxWinRate)
+		return
 	}
 
-	scoreMap := getScoreMap()
 	partOne(rounds, scoreMap)
 	partTwo(rounds, scoreMap)
 }
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+//go:embed rules/*.json
+var presetFiles embed.FS
+
+// presets maps the names accepted on the command line to the shipped rule sets
+var presets = map[string]string{
+	"rps":   "rules/rps.json",
+	"rpsls": "rules/rpsls.json",
+}
+
+const defaultPreset = "rps"
+
+// moveRule is how a move is written, scored and what it defeats
+type moveRule struct {
+	Name     string   `json:"name"`
+	Score    int      `json:"score"`
+	Opponent string   `json:"opponent"`
+	Response string   `json:"response"`
+	Beats    []string `json:"beats"`
+}
+
+type outcomeRule struct {
+	Score  int    `json:"score"`
+	Letter string `json:"letter"`
+}
+
+// ruleSet describes a Rock Paper Scissors like game and its cipher.
+//
+// The file format mirrors the struct: the moves with their shape score,
+// cipher letters and the moves they beat, and the score and letter of
+// every outcome. After loading, the rules are validated and indexed.
+type ruleSet struct {
+	Name     string                 `json:"name"`
+	Moves    []moveRule             `json:"moves"`
+	Outcomes map[string]outcomeRule `json:"outcomes"`
+
+	beats           [][]bool
+	opponentLetters map[byte]move
+	responseLetters map[byte]move
+	outcomeLetters  map[byte]outcome
+	outcomeScores   [outcomeCount]int
+}
+
+func singleLetter(s string, what string) (byte, error) {
+	if len(s) != 1 {
+		return 0, fmt.Errorf("%s letter %q must be a single character", what, s)
+	}
+	return s[0], nil
+}
+
+// index validates the rules and builds the lookup tables.
+//
+// The moves have to form a tournament: every two different moves are
+// decided one way or the other, no move beats itself and every move
+// can both win and lose, otherwise part two could not be played.
+func (rules *ruleSet) index() error {
+	n := len(rules.Moves)
+	if n < 2 {
+		return fmt.Errorf("rule set %q needs at least two moves", rules.Name)
+	}
+
+	moves := make(map[string]move)
+	rules.opponentLetters = make(map[byte]move)
+	rules.responseLetters = make(map[byte]move)
+	for i, m := range rules.Moves {
+		if _, ok := moves[m.Name]; ok || m.Name == "" {
+			return fmt.Errorf("invalid or duplicate move name %q", m.Name)
+		}
+		moves[m.Name] = move(i)
+
+		opponent, err := singleLetter(m.Opponent, "opponent")
+		if err != nil {
+			return err
+		}
+		response, err := singleLetter(m.Response, "response")
+		if err != nil {
+			return err
+		}
+		if _, ok := rules.opponentLetters[opponent]; ok {
+			return fmt.Errorf("opponent letter %q is used twice", opponent)
+		}
+		if _, ok := rules.responseLetters[response]; ok {
+			return fmt.Errorf("response letter %q is used twice", response)
+		}
+		rules.opponentLetters[opponent] = move(i)
+		rules.responseLetters[response] = move(i)
+	}
+
+	rules.beats = make([][]bool, n)
+	for i := range rules.beats {
+		rules.beats[i] = make([]bool, n)
+	}
+	for i, m := range rules.Moves {
+		for _, name := range m.Beats {
+			beaten, ok := moves[name]
+			if !ok {
+				return fmt.Errorf("%s beats unknown move %q", m.Name, name)
+			}
+			if beaten == move(i) {
+				return fmt.Errorf("%s cannot beat itself", m.Name)
+			}
+			rules.beats[i][beaten] = true
+		}
+	}
+
+	for a := 0; a < n; a++ {
+		wins, losses := 0, 0
+		for b := 0; b < n; b++ {
+			if a == b {
+				continue
+			}
+			if rules.beats[a][b] == rules.beats[b][a] {
+				return fmt.Errorf("exactly one of %s and %s must beat the other", rules.Moves[a].Name, rules.Moves[b].Name)
+			}
+			if rules.beats[a][b] {
+				wins++
+			} else {
+				losses++
+			}
+		}
+		if wins == 0 || losses == 0 {
+			return fmt.Errorf("%s has to beat and lose to at least one move", rules.Moves[a].Name)
+		}
+	}
+
+	rules.outcomeLetters = make(map[byte]outcome)
+	for o := loss; o < outcomeCount; o++ {
+		rule, ok := rules.Outcomes[o.String()]
+		if !ok {
+			return fmt.Errorf("missing outcome %q", o.String())
+		}
+		letter, err := singleLetter(rule.Letter, "outcome")
+		if err != nil {
+			return err
+		}
+		if _, ok := rules.outcomeLetters[letter]; ok {
+			return fmt.Errorf("outcome letter %q is used twice", letter)
+		}
+		rules.outcomeLetters[letter] = o
+		rules.outcomeScores[o] = rule.Score
+	}
+	if len(rules.Outcomes) != int(outcomeCount) {
+		return fmt.Errorf("only loss, draw and win outcomes are allowed")
+	}
+
+	return nil
+}
+
+func parseRuleSet(data []byte) (*ruleSet, error) {
+	rules := &ruleSet{}
+	if err := json.Unmarshal(data, rules); err != nil {
+		return nil, err
+	}
+	if err := rules.index(); err != nil {
+		return nil, fmt.Errorf("rule set %q: %w", rules.Name, err)
+	}
+	return rules, nil
+}
+
+func loadPreset(name string) (*ruleSet, error) {
+	path, ok := presets[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preset %q", name)
+	}
+	data, err := presetFiles.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseRuleSet(data)
+}
+
+func loadRuleSet(filePath string) (*ruleSet, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return parseRuleSet(data)
+}
+
+func (rules *ruleSet) moveCount() int {
+	return len(rules.Moves)
+}
+
+func (rules *ruleSet) moveName(m move) string {
+	return rules.Moves[m].Name
+}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// contestant is a player of the tournament and the moves it will play.
+//
+// A contestant is read from a strategy guide, spec is the path of the
+// guide optionally followed by how to read it: ":part1" (the default)
+// plays the second column as moves, ":part2" plays it as outcomes
+// against the predicted opponent, and ":opponent" plays the first column.
+// A guide facing its own opponent column scores exactly like part one.
+type contestant struct {
+	name  string
+	moves []move
+}
+
+// matchResult is a pairing played round by round in the order of the guides
+type matchResult struct {
+	a      int
+	b      int
+	scoreA int
+	scoreB int
+	winsA  int
+	winsB  int
+	draws  int
+}
+
+type standing struct {
+	player     int
+	played     int
+	won        int
+	drawn      int
+	lost       int
+	points     int
+	score      int
+	roundWins  int
+	headToHead int
+}
+
+func loadContestant(spec string, matrix payoffMatrix) (contestant, error) {
+	path, mode := spec, "part1"
+	if i := strings.LastIndex(spec, ":"); i >= 0 {
+		path, mode = spec[:i], spec[i+1:]
+	}
+
+	rounds, err := readRounds(path, matrix.rules)
+	if err != nil {
+		return contestant{}, err
+	}
+
+	c := contestant{name: filepath.Base(path), moves: make([]move, len(rounds))}
+	if mode != "part1" {
+		c.name += ":" + mode
+	}
+	for i, r := range rounds {
+		switch mode {
+		case "part1":
+			c.moves[i], err = asMove(r, matrix)
+		case "part2":
+			c.moves[i], err = asOutcome(r, matrix)
+		case "opponent":
+			c.moves[i] = r.opponent
+		default:
+			return contestant{}, fmt.Errorf("unknown mode %q for %s", mode, path)
+		}
+		if err != nil {
+			return contestant{}, fmt.Errorf("%s round %d: %w", path, i+1, err)
+		}
+	}
+	return c, nil
+}
+
+// playMatch scores both players with the rules of part one.
+// The match lasts as long as the shorter of the two guides.
+func playMatch(a int, b int, players []contestant, matrix payoffMatrix) matchResult {
+	result := matchResult{a: a, b: b}
+	movesA, movesB := players[a].moves, players[b].moves
+	for i := 0; i < len(movesA) && i < len(movesB); i++ {
+		result.scoreA += matrix.scores[movesB[i]][movesA[i]]
+		result.scoreB += matrix.scores[movesA[i]][movesB[i]]
+		switch matrix.outcomes[movesB[i]][movesA[i]] {
+		case win:
+			result.winsA++
+		case loss:
+			result.winsB++
+		default:
+			result.draws++
+		}
+	}
+	return result
+}
+
+// playMatches plays the given pairings, each in its own goroutine if asked
+func playMatches(pairings [][2]int, players []contestant, matrix payoffMatrix, concurrent bool) []matchResult {
+	results := make([]matchResult, len(pairings))
+	if !concurrent {
+		for i, p := range pairings {
+			results[i] = playMatch(p[0], p[1], players, matrix)
+		}
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for i, p := range pairings {
+		wg.Add(1)
+		go func(i int, a int, b int) {
+			defer wg.Done()
+			results[i] = playMatch(a, b, players, matrix)
+		}(i, p[0], p[1])
+	}
+	wg.Wait()
+	return results
+}
+
+// matchPoints gives 3 points for a won match and 1 for a draw,
+// the match being won by the higher total score
+func matchPoints(own int, other int) int {
+	if own > other {
+		return 3
+	} else if own == other {
+		return 1
+	}
+	return 0
+}
+
+func (s *standing) record(own int, other int, roundWins int) {
+	s.played++
+	s.points += matchPoints(own, other)
+	s.score += own
+	s.roundWins += roundWins
+	if own > other {
+		s.won++
+	} else if own == other {
+		s.drawn++
+	} else {
+		s.lost++
+	}
+}
+
+// roundRobin pairs every player with every other player once.
+//
+// Standings are sorted by points, then by the points won in the matches
+// between the tied players, then by total score, round wins and finally
+// by the order the players were given in.
+func roundRobin(players []contestant, matrix payoffMatrix, concurrent bool) ([]matchResult, []standing) {
+	pairings := [][2]int{}
+	for a := 0; a < len(players); a++ {
+		for b := a + 1; b < len(players); b++ {
+			pairings = append(pairings, [2]int{a, b})
+		}
+	}
+	results := playMatches(pairings, players, matrix, concurrent)
+
+	standings := make([]standing, len(players))
+	for i := range standings {
+		standings[i].player = i
+	}
+	for _, r := range results {
+		standings[r.a].record(r.scoreA, r.scoreB, r.winsA)
+		standings[r.b].record(r.scoreB, r.scoreA, r.winsB)
+	}
+
+	points := make([]int, len(players))
+	for _, s := range standings {
+		points[s.player] = s.points
+	}
+	for _, r := range results {
+		if points[r.a] == points[r.b] {
+			standings[r.a].headToHead += matchPoints(r.scoreA, r.scoreB)
+			standings[r.b].headToHead += matchPoints(r.scoreB, r.scoreA)
+		}
+	}
+
+	sort.SliceStable(standings, func(i, j int) bool {
+		a, b := standings[i], standings[j]
+		if a.points != b.points {
+			return a.points > b.points
+		}
+		if a.headToHead != b.headToHead {
+			return a.headToHead > b.headToHead
+		}
+		if a.score != b.score {
+			return a.score > b.score
+		}
+		return a.roundWins > b.roundWins
+	})
+	return results, standings
+}
+
+// bracketOrder places the seeds so that the best ones meet as late as possible
+func bracketOrder(size int) []int {
+	order := []int{0}
+	for len(order) < size {
+		next := make([]int, 0, 2*len(order))
+		for _, seed := range order {
+			next = append(next, seed, 2*len(order)-1-seed)
+		}
+		order = next
+	}
+	return order
+}
+
+// matchWinner decides a match by score, then by round wins, then by seed
+func matchWinner(r matchResult) int {
+	if r.scoreA != r.scoreB {
+		if r.scoreA > r.scoreB {
+			return r.a
+		}
+		return r.b
+	}
+	if r.winsA != r.winsB {
+		if r.winsA > r.winsB {
+			return r.a
+		}
+		return r.b
+	}
+	if r.a < r.b {
+		return r.a
+	}
+	return r.b
+}
+
+// bracket plays a single elimination tournament seeded in the given order.
+// Missing players are byes, which send their opponent straight through.
+func bracket(players []contestant, matrix payoffMatrix, concurrent bool) ([][]matchResult, int) {
+	size := 1
+	for size < len(players) {
+		size *= 2
+	}
+
+	alive := []int{}
+	for _, seed := range bracketOrder(size) {
+		if seed < len(players) {
+			alive = append(alive, seed)
+		} else {
+			alive = append(alive, -1)
+		}
+	}
+
+	rounds := [][]matchResult{}
+	for len(alive) > 1 {
+		pairings := [][2]int{}
+		next := make([]int, len(alive)/2)
+		for i := 0; i < len(alive); i += 2 {
+			a, b := alive[i], alive[i+1]
+			if a == -1 {
+				next[i/2] = b
+				continue
+			}
+			if b == -1 {
+				next[i/2] = a
+				continue
+			}
+			pairings = append(pairings, [2]int{a, b})
+			next[i/2] = -2
+		}
+
+		results := playMatches(pairings, players, matrix, concurrent)
+		played := 0
+		for i := range next {
+			if next[i] == -2 {
+				next[i] = matchWinner(results[played])
+				played++
+			}
+		}
+		rounds = append(rounds, results)
+		alive = next
+	}
+	return rounds, alive[0]
+}
+
+func printMatch(r matchResult, players []contestant) {
+	fmt.Printf("  %s %d - %d %s (%d-%d-%d)\n",
+		players[r.a].name, r.scoreA, r.scoreB, players[r.b].name, r.winsA, r.draws, r.winsB)
+}
+
+func runTournament(specs []string, format string, matrix payoffMatrix, concurrent bool) error {
+	if len(specs) < 2 {
+		return fmt.Errorf("a tournament needs at least two players")
+	}
+	players := make([]contestant, len(specs))
+	for i, spec := range specs {
+		c, err := loadContestant(spec, matrix)
+		if err != nil {
+			return err
+		}
+		players[i] = c
+	}
+
+	switch format {
+	case "round-robin":
+		results, standings := roundRobin(players, matrix, concurrent)
+		fmt.Println("Matches:")
+		for _, r := range results {
+			printMatch(r, players)
+		}
+		fmt.Println()
+		fmt.Printf("%-4s %-24s %3s %3s %3s %3s %6s %8s %6s\n", "#", "Player", "P", "W", "D", "L", "Points", "Score", "Rounds")
+		for i, s := range standings {
+			fmt.Printf("%-4d %-24s %3d %3d %3d %3d %6d %8d %6d\n",
+				i+1, players[s.player].name, s.played, s.won, s.drawn, s.lost, s.points, s.score, s.roundWins)
+		}
+	case "bracket":
+		rounds, champion := bracket(players, matrix, concurrent)
+		for i, results := range rounds {
+			fmt.Printf("Round %d:\n", i+1)
+			for _, r := range results {
+				printMatch(r, players)
+			}
+		}
+		fmt.Println("Champion:", players[champion].name)
+	default:
+		return fmt.Errorf("unknown tournament format %q", format)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func exampleMatrix(t *testing.T) payoffMatrix {
+	t.Helper()
+	rules, err := loadPreset(defaultPreset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return getScoreMap(rules)
+}
+
+// writeExample saves the example guide for the contestants to read
+func writeExample(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "example.txt")
+	if err := os.WriteFile(path, []byte(example), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func loadContestants(t *testing.T, matrix payoffMatrix, specs ...string) []contestant {
+	t.Helper()
+	players := make([]contestant, len(specs))
+	for i, spec := range specs {
+		c, err := loadContestant(spec, matrix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		players[i] = c
+	}
+	return players
+}
+
+// TestSinglePairing plays the guide against its own opponent column,
+// which scores like the puzzle: 15 reading the moves, 12 the outcomes
+func TestSinglePairing(t *testing.T) {
+	matrix := exampleMatrix(t)
+	path := writeExample(t)
+	tests := []struct {
+		spec  string
+		score int
+	}{
+		{path, 15},
+		{path + ":part1", 15},
+		{path + ":part2", 12},
+	}
+	for _, tt := range tests {
+		players := loadContestants(t, matrix, tt.spec, path+":opponent")
+		for _, concurrent := range []bool{false, true} {
+			results, standings := roundRobin(players, matrix, concurrent)
+			if len(results) != 1 || results[0].scoreA != tt.score {
+				t.Fatalf("%s: %v, expected a single match scoring %d", tt.spec, results, tt.score)
+			}
+			if standings[0].played != 1 || standings[1].played != 1 {
+				t.Fatalf("%s: standings %v", tt.spec, standings)
+			}
+		}
+	}
+}
+
+func TestLoadContestantMode(t *testing.T) {
+	matrix := exampleMatrix(t)
+	if _, err := loadContestant(writeExample(t)+":part3", matrix); err == nil {
+		t.Fatal("unknown mode accepted")
+	}
+}
+
+func TestBracket(t *testing.T) {
+	if order := bracketOrder(8); !slices.Equal(order, []int{0, 7, 3, 4, 1, 6, 2, 5}) {
+		t.Fatalf("bracket order %v", order)
+	}
+
+	matrix := exampleMatrix(t)
+	path := writeExample(t)
+	// three players leave a bye for the first seed, who meets the
+	// winner of the other two in the final
+	players := loadContestants(t, matrix, path+":opponent", path, path+":part2")
+	rounds, champion := bracket(players, matrix, false)
+	if len(rounds) != 2 || len(rounds[0]) != 1 || len(rounds[1]) != 1 {
+		t.Fatalf("rounds %v", rounds)
+	}
+	first, final := rounds[0][0], rounds[1][0]
+	if first.a != 1 || first.b != 2 || final.a != 0 || final.b != matchWinner(first) {
+		t.Fatalf("first round %v, final %v", first, final)
+	}
+	// the final is a draw, 15 to 15 with a win each, won by the first seed
+	if champion != matchWinner(final) || champion != 0 {
+		t.Fatalf("champion %d after the final %v", champion, final)
+	}
+}
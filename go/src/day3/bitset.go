@@ -0,0 +1,121 @@
+package main
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// itemSet is a set of item types, bit p is set when
+// the item type with priority p is in the set.
+// The 52 priorities fit in a single 64-bit word.
+type itemSet uint64
+
+func itemPriority(item byte) (int, error) {
+	switch {
+	case item >= 'a' && item <= 'z':
+		return int(item-'a') + 1, nil
+	case item >= 'A' && item <= 'Z':
+		return int(item-'A') + 27, nil
+	}
+	return 0, fmt.Errorf("invalid item %q", item)
+}
+
+func parseItemSet(items string) (itemSet, error) {
+	var set itemSet
+	for i := 0; i < len(items); i++ {
+		priority, err := itemPriority(items[i])
+		if err != nil {
+			return 0, err
+		}
+		set |= 1 << priority
+	}
+	return set, nil
+}
+
+func (s itemSet) size() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// single returns the priority of the only item in the set
+func (s itemSet) single() (int, error) {
+	if s.size() != 1 {
+		return 0, fmt.Errorf("expected exactly one shared item, found %d", s.size())
+	}
+	return bits.TrailingZeros64(uint64(s)), nil
+}
+
+// compartmentSets splits a rucksack into equally sized compartments
+func compartmentSets(rucksack string, compartments int) ([]itemSet, error) {
+	if compartments < 1 {
+		return nil, fmt.Errorf("a rucksack needs at least one compartment, not %d", compartments)
+	}
+	if len(rucksack)%compartments != 0 {
+		return nil, fmt.Errorf("%d items cannot be split into %d compartments", len(rucksack), compartments)
+	}
+
+	size := len(rucksack) / compartments
+	sets := make([]itemSet, compartments)
+	for i := range sets {
+		set, err := parseItemSet(rucksack[i*size : (i+1)*size])
+		if err != nil {
+			return nil, err
+		}
+		sets[i] = set
+	}
+	return sets, nil
+}
+
+func intersectAll(sets []itemSet) itemSet {
+	shared := ^itemSet(0)
+	for _, set := range sets {
+		shared &= set
+	}
+	return shared
+}
+
+// compartmentPrioritySum adds up the priority of the item found
+// in every compartment of each rucksack
+func compartmentPrioritySum(rucksacks []string, compartments int) (int, error) {
+	prioritySum := 0
+	for i, rucksack := range rucksacks {
+		sets, err := compartmentSets(rucksack, compartments)
+		if err != nil {
+			return 0, fmt.Errorf("rucksack %d: %w", i+1, err)
+		}
+		priority, err := intersectAll(sets).single()
+		if err != nil {
+			return 0, fmt.Errorf("rucksack %d: %w", i+1, err)
+		}
+		prioritySum += priority
+	}
+	return prioritySum, nil
+}
+
+// badgePrioritySum adds up the priority of the badge of every group,
+// the only item type carried by all the Elves of the group
+func badgePrioritySum(rucksacks []string, groupSize int) (int, error) {
+	if groupSize < 1 {
+		return 0, fmt.Errorf("a group needs at least one Elf, not %d", groupSize)
+	}
+	if len(rucksacks)%groupSize != 0 {
+		return 0, fmt.Errorf("%d rucksacks cannot form groups of %d, the last group is incomplete", len(rucksacks), groupSize)
+	}
+
+	prioritySum := 0
+	for i := 0; i < len(rucksacks); i += groupSize {
+		sets := make([]itemSet, groupSize)
+		for j := range sets {
+			set, err := parseItemSet(rucksacks[i+j])
+			if err != nil {
+				return 0, fmt.Errorf("rucksack %d: %w", i+j+1, err)
+			}
+			sets[j] = set
+		}
+		priority, err := intersectAll(sets).single()
+		if err != nil {
+			return 0, fmt.Errorf("group %d: %w", i/groupSize+1, err)
+		}
+		prioritySum += priority
+	}
+	return prioritySum, nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"parsing"
+	"strings"
+	"testing"
+)
+
+// readBenchmarkInput returns the committed rucksacks, as lines and split
+// into items for the map based solutions
+func readBenchmarkInput(b *testing.B) ([]string, [][]string) {
+	file, err := os.Open("../../input/day3.txt")
+	if err != nil {
+		b.Skip(err)
+	}
+	defer file.Close()
+
+	rucksacks, err := parsing.ReadLines(file)
+	if err != nil {
+		b.Fatal(err)
+	}
+	split := make([][]string, len(rucksacks))
+	for i, rucksack := range rucksacks {
+		split[i] = strings.Split(rucksack, "")
+	}
+	b.ReportAllocs()
+	b.ResetTimer()
+	return rucksacks, split
+}
+
+func BenchmarkMapCompartments(b *testing.B) {
+	_, split := readBenchmarkInput(b)
+	for i := 0; i < b.N; i++ {
+		getCompartmentPrioritySum(split)
+	}
+}
+
+func BenchmarkBitsetCompartments(b *testing.B) {
+	rucksacks, _ := readBenchmarkInput(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := compartmentPrioritySum(rucksacks, 2); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkMapBadges(b *testing.B) {
+	_, split := readBenchmarkInput(b)
+	for i := 0; i < b.N; i++ {
+		getBadgePrioritySum(split)
+	}
+}
+
+func BenchmarkBitsetBadges(b *testing.B) {
+	rucksacks, _ := readBenchmarkInput(b)
+	for i := 0; i < b.N; i++ {
+		if _, err := badgePrioritySum(rucksacks, 3); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
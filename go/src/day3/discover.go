@@ -0,0 +1,227 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// groupSearch partitions rucksacks into groups whose members share exactly
+// one item type, when nobody remembers who was in which group
+type groupSearch struct {
+	sets      []itemSet
+	groupSize int
+	assigned  []bool
+	groups    [][]int
+	solution  [][]int
+	solutions int
+	nodes     int
+	budget    int
+	exhausted bool
+}
+
+// eachGroup calls found with every possible group of unassigned rucksacks
+// containing r until found returns false. The running intersection of the
+// members is kept along the way so that hopeless groups are cut early.
+func (s *groupSearch) eachGroup(r int, found func(group []int) bool) {
+	group := []int{r}
+	var extend func(from int, shared itemSet) bool
+	extend = func(from int, shared itemSet) bool {
+		if len(group) == s.groupSize {
+			if shared.size() != 1 {
+				return true
+			}
+			return found(group)
+		}
+		for other := from; other < len(s.sets); other++ {
+			if s.assigned[other] || other == r {
+				continue
+			}
+			next := shared & s.sets[other]
+			if next == 0 {
+				continue
+			}
+			group = append(group, other)
+			ok := extend(other+1, next)
+			group = group[:len(group)-1]
+			if !ok {
+				return false
+			}
+		}
+		return true
+	}
+	extend(0, s.sets[r])
+}
+
+// countGroups counts the groups r could still join, stopping at limit
+func (s *groupSearch) countGroups(r int, limit int) int {
+	count := 0
+	s.eachGroup(r, func(group []int) bool {
+		count++
+		return count < limit
+	})
+	return count
+}
+
+// pick returns the unassigned rucksack with the fewest possible groups.
+// A rucksack without any group means this branch is dead, one with a
+// single possible group forces it, so both are returned right away.
+func (s *groupSearch) pick() (int, int) {
+	best, bestCount := -1, 0
+	for r := range s.sets {
+		if s.assigned[r] {
+			continue
+		}
+		count := s.countGroups(r, 2)
+		if count <= 1 {
+			return r, count
+		}
+		if best == -1 || count < bestCount {
+			best, bestCount = r, count
+		}
+	}
+	return best, bestCount
+}
+
+// search looks for partitions and returns true once it can stop:
+// after the second solution, which settles uniqueness, or out of budget
+func (s *groupSearch) search(remaining int) bool {
+	if remaining == 0 {
+		s.solutions++
+		if s.solutions == 1 {
+			for _, group := range s.groups {
+				s.solution = append(s.solution, append([]int{}, group...))
+			}
+		}
+		return s.solutions >= 2
+	}
+
+	s.nodes++
+	if s.nodes > s.budget {
+		s.exhausted = true
+		return true
+	}
+
+	r, count := s.pick()
+	if count == 0 {
+		return false
+	}
+
+	stop := false
+	s.eachGroup(r, func(group []int) bool {
+		members := append([]int{}, group...)
+		for _, m := range members {
+			s.assigned[m] = true
+		}
+		s.groups = append(s.groups, members)
+
+		stop = s.search(remaining - len(members))
+
+		s.groups = s.groups[:len(s.groups)-1]
+		for _, m := range members {
+			s.assigned[m] = false
+		}
+		return !stop
+	})
+	return stop
+}
+
+// discovery is a partition of the rucksacks into badge groups
+type discovery struct {
+	groups  [][]int
+	badges  []int
+	unique  bool
+	unknown bool
+}
+
+// discoverGroups finds a partition of the rucksacks into badge groups
+// and tells whether it is the only one. unknown is set when the search
+// ran out of budget before settling the question.
+func discoverGroups(rucksacks []string, groupSize int, budget int) (discovery, error) {
+	if len(rucksacks)%groupSize != 0 {
+		return discovery{}, fmt.Errorf("%d rucksacks cannot form groups of %d", len(rucksacks), groupSize)
+	}
+
+	s := &groupSearch{
+		sets:      make([]itemSet, len(rucksacks)),
+		groupSize: groupSize,
+		assigned:  make([]bool, len(rucksacks)),
+		budget:    budget,
+	}
+	for i, rucksack := range rucksacks {
+		set, err := parseItemSet(rucksack)
+		if err != nil {
+			return discovery{}, fmt.Errorf("rucksack %d: %w", i+1, err)
+		}
+		s.sets[i] = set
+	}
+
+	s.search(len(rucksacks))
+	if s.solutions == 0 {
+		if s.exhausted {
+			return discovery{unknown: true}, fmt.Errorf("no partition found within %d steps", budget)
+		}
+		return discovery{}, fmt.Errorf("the rucksacks cannot be split into badge groups")
+	}
+
+	d := discovery{
+		groups:  s.solution,
+		unique:  s.solutions == 1 && !s.exhausted,
+		unknown: s.exhausted,
+	}
+	for _, group := range d.groups {
+		sets := make([]itemSet, len(group))
+		for j, r := range group {
+			sets[j] = s.sets[r]
+		}
+		badge, _ := intersectAll(sets).single()
+		d.badges = append(d.badges, badge)
+	}
+	return d, nil
+}
+
+// shuffleRucksacks mixes the rucksacks up and remembers
+// the original line of every one of them
+func shuffleRucksacks(rucksacks []string, seed int64) ([]string, []int) {
+	shuffled := append([]string{}, rucksacks...)
+	lines := make([]int, len(rucksacks))
+	for i := range lines {
+		lines[i] = i + 1
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+		lines[i], lines[j] = lines[j], lines[i]
+	})
+	return shuffled, lines
+}
+
+// printDiscovery reports the groups by the original line numbers
+func printDiscovery(rucksacks []string, lines []int, groupSize int, budget int) {
+	d, err := discoverGroups(rucksacks, groupSize, budget)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	prioritySum := 0
+	for i, group := range d.groups {
+		members := make([]int, len(group))
+		for j, r := range group {
+			members[j] = lines[r]
+		}
+		prioritySum += d.badges[i]
+		fmt.Printf("Group %d: lines %v, badge priority %d\n", i+1, members, d.badges[i])
+	}
+
+	fmt.Println()
+	fmt.Println("Sum of priorities: ", prioritySum)
+	switch {
+	case d.unknown:
+		fmt.Println("Partition is unique: unknown, the search ran out of budget")
+	case d.unique:
+		fmt.Println("Partition is unique: yes")
+	default:
+		fmt.Println("Partition is unique: no")
+	}
+}
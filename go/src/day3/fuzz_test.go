@@ -0,0 +1,119 @@
+package main
+
+import (
+	"os"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const example = `vJrwpWtwJgWrhcsFMMfFFhFp
+jqHRNqRjqzjGDLGLrsFMfFZSrLrFZsSL
+PmmdzqPrVvPwwTWBwg
+wMqvLMZHhHMvwLHjbvcjnnSBnvTQFn
+ttgJtRGJQctTZtZT
+CrZsJsPPZsGzwwsLwLmpwMDw
+`
+
+func addInputs(f *testing.F) {
+	f.Add(example, uint8(2))
+	f.Add(example, uint8(3))
+	if input, err := os.ReadFile("../../input/day3.txt"); err == nil {
+		f.Add(string(input), uint8(2))
+		f.Add(string(input), uint8(3))
+	}
+}
+
+func readRucksacks(data string) []string {
+	return strings.FieldsFunc(data, func(r rune) bool {
+		return r == '\n'
+	})
+}
+
+// FuzzPrioritySums splits the rucksacks into size compartments and the
+// Elves into groups of size. The bitset solutions must reject the input
+// or find a priority for every rucksack and group, the same as the
+// original map based ones for the puzzle's two compartments and groups
+// of three.
+func FuzzPrioritySums(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data string, size uint8) {
+		rucksacks := readRucksacks(data)
+		split := make([][]string, len(rucksacks))
+		for i, rucksack := range rucksacks {
+			split[i] = strings.Split(rucksack, "")
+		}
+
+		if sum, err := compartmentPrioritySum(rucksacks, int(size)); err == nil {
+			if sum < len(rucksacks) || sum > 52*len(rucksacks) {
+				t.Fatalf("compartments: sum %d for %d rucksacks", sum, len(rucksacks))
+			}
+			if size == 2 {
+				if want := getCompartmentPrioritySum(split); sum != want {
+					t.Fatalf("compartments: bitset sum %d, map sum %d", sum, want)
+				}
+				if data == example && sum != 157 {
+					t.Fatalf("compartments: example sum %d, expected 157", sum)
+				}
+			}
+		}
+		if sum, err := badgePrioritySum(rucksacks, int(size)); err == nil {
+			groups := len(rucksacks) / int(size)
+			if sum < groups || sum > 52*groups {
+				t.Fatalf("badges: sum %d for %d groups", sum, groups)
+			}
+			if size == 3 {
+				if want := getBadgePrioritySum(split); sum != want {
+					t.Fatalf("badges: bitset sum %d, map sum %d", sum, want)
+				}
+				if data == example && sum != 70 {
+					t.Fatalf("badges: example sum %d, expected 70", sum)
+				}
+			}
+		}
+	})
+}
+
+// FuzzRepack checks that a repacked rucksack holds the same items, with
+// every item type in a single compartment
+//
+// The search is exponential in the number of compartments, so there are
+// at most four and the seeds are the short rucksacks of the example.
+func FuzzRepack(f *testing.F) {
+	f.Add(example, uint8(2))
+	f.Add("aAbBaAbB\naabb\n", uint8(4))
+	f.Fuzz(func(t *testing.T, data string, compartments uint8) {
+		n := int(compartments%4) + 1
+		for _, rucksack := range readRucksacks(data) {
+			fixed, moves, err := planRepack(rucksack, n)
+			if err != nil {
+				continue
+			}
+
+			before, after := []byte(rucksack), []byte(fixed)
+			slices.Sort(before)
+			slices.Sort(after)
+			if !slices.Equal(before, after) {
+				t.Fatalf("repacking %q gave %q", rucksack, fixed)
+			}
+			changed := 0
+			for i := range rucksack {
+				if rucksack[i] != fixed[i] {
+					changed++
+				}
+			}
+			if moves < 0 || changed > moves {
+				t.Fatalf("repacking %q into %q changed %d places in %d moves", rucksack, fixed, changed, moves)
+			}
+
+			size := len(fixed) / n
+			home := make(map[byte]int)
+			for i := 0; i < len(fixed); i++ {
+				if c, ok := home[fixed[i]]; ok && c != i/size {
+					t.Fatalf("%q is in two compartments of %q", fixed[i], fixed)
+				}
+				home[fixed[i]] = i / size
+			}
+		}
+	})
+}
@@ -102,9 +102,10 @@ What is the sum of the priorities of those item types?
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"os"
+	"parsing"
 	"strings"
 )
 
@@ -143,7 +144,9 @@ func getSet(items []string) Set {
 	return set
 }
 
-func partOne(rucksacks [][]string) {
+// getCompartmentPrioritySum is the original map based part one,
+// kept to compare it with the bitset version
+func getCompartmentPrioritySum(rucksacks [][]string) int {
 	prioritySum := 0
 	for _, rucksack := range rucksacks {
 		firstHalf := rucksack[:len(rucksack)/2]
@@ -153,11 +156,11 @@ func partOne(rucksacks [][]string) {
 		intersection := setIntersection(set1, set2)
 		prioritySum += getSetPriorityTotal(intersection)
 	}
-
-	fmt.Println("Sum of priorities: ", prioritySum)
+	return prioritySum
 }
 
-func partTwo(rucksacks [][]string) {
+// getBadgePrioritySum is the original map based part two
+func getBadgePrioritySum(rucksacks [][]string) int {
 	prioritySum := 0
 	for i := 0; i < len(rucksacks); i += 3 {
 		set1 := getSet(rucksacks[i])
@@ -168,11 +171,43 @@ func partTwo(rucksacks [][]string) {
 		intersection = setIntersection(intersection, set3)
 		prioritySum += getSetPriorityTotal(intersection)
 	}
+	return prioritySum
+}
+
+func partOne(rucksacks []string, compartments int) {
+	prioritySum, err := compartmentPrioritySum(rucksacks, compartments)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	fmt.Println("Sum of priorities: ", prioritySum)
+}
+
+func partTwo(rucksacks []string, groupSize int) {
+	prioritySum, err := badgePrioritySum(rucksacks, groupSize)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
 
 	fmt.Println("Sum of priorities: ", prioritySum)
 }
 
 func main() {
+	compartments := flag.Int("compartments", 2, "number of compartments in every rucksack")
+	groupSize := flag.Int("group", 3, "number of Elves in a group")
+	repack := flag.Bool("repack", false, "plan the fewest moves so no item type is in two compartments")
+	discover := flag.Bool("discover", false, "find the badge groups without assuming consecutive lines")
+	shuffle := flag.Int64("shuffle", 0, "shuffle the rucksacks with this seed before discovering groups")
+	budget := flag.Int("budget", 1000000, "maximum number of search steps when discovering groups")
+	flag.Parse()
+
+	if *compartments < 1 || *groupSize < 1 {
+		fmt.Println("Compartments and group size must be positive")
+		return
+	}
+
 	file, err := os.Open("../../input/day3.txt")
 	if err != nil {
 		fmt.Println(err)
@@ -180,19 +215,31 @@ func main() {
 	}
 	defer file.Close()
 
-	rucksacks := make([][]string, 0)
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		rucksack := make([]string, 0)
-		for _, item := range strings.Split(scanner.Text(), "") {
-			rucksack = append(rucksack, item)
+	rucksacks, err := parsing.ReadLines(file)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	if *discover {
+		lines := make([]int, len(rucksacks))
+		for i := range lines {
+			lines[i] = i + 1
+		}
+		if *shuffle != 0 {
+			rucksacks, lines = shuffleRucksacks(rucksacks, *shuffle)
 		}
-		rucksacks = append(rucksacks, rucksack)
+		printDiscovery(rucksacks, lines, *groupSize, *budget)
+		return
+	}
+	if *repack {
+		printRepacks(planRepacks(rucksacks, *compartments), *compartments)
+		return
 	}
 
 	fmt.Println("------- Part One -------")
-	partOne(rucksacks)
+	partOne(rucksacks, *compartments)
 	fmt.Println()
 	fmt.Println("------- Part Two -------")
-	partTwo(rucksacks)
+	partTwo(rucksacks, *groupSize)
 }
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// repackPlan is the fix for a single rucksack
+type repackPlan struct {
+	rucksack string
+	fixed    string
+	moves    int
+	err      error
+}
+
+// planRepack moves as few items as possible between the compartments so
+// that every item type ends up in a single compartment, keeping all the
+// compartments the same size.
+//
+// Every item type is assigned to one compartment as a whole, the items of
+// that type found elsewhere are the ones that move. Picking the assignment
+// is a small packing problem that we solve exactly by dynamic programming
+// over the space left in each compartment. The moved items take the places
+// freed in their new compartment, so the other items keep their positions.
+func planRepack(rucksack string, compartments int) (string, int, error) {
+	if compartments < 1 {
+		return "", 0, fmt.Errorf("a rucksack needs at least one compartment, not %d", compartments)
+	}
+	if len(rucksack)%compartments != 0 {
+		return "", 0, fmt.Errorf("%d items cannot be split into %d compartments", len(rucksack), compartments)
+	}
+	size := len(rucksack) / compartments
+
+	types := []byte{}
+	counts := make(map[byte][]int)
+	for i := 0; i < len(rucksack); i++ {
+		item := rucksack[i]
+		if _, err := itemPriority(item); err != nil {
+			return "", 0, err
+		}
+		if counts[item] == nil {
+			counts[item] = make([]int, compartments)
+			types = append(types, item)
+		}
+		counts[item][i/size]++
+	}
+
+	totals := make([]int, len(types))
+	for t, item := range types {
+		for _, c := range counts[item] {
+			totals[t] += c
+		}
+		if totals[t] > size {
+			return "", 0, fmt.Errorf("%d items of type %c do not fit in a compartment of %d", totals[t], item, size)
+		}
+	}
+
+	// best returns the fewest moves for the types from t on
+	// given the space left in every compartment, or -1
+	memo := make(map[string]int)
+	var best func(t int, space []int) int
+	best = func(t int, space []int) int {
+		if t == len(types) {
+			return 0
+		}
+		key := strconv.Itoa(t) + fmt.Sprint(space)
+		if moves, ok := memo[key]; ok {
+			return moves
+		}
+
+		result := -1
+		for c := range space {
+			if space[c] < totals[t] {
+				continue
+			}
+			space[c] -= totals[t]
+			rest := best(t+1, space)
+			space[c] += totals[t]
+			if rest < 0 {
+				continue
+			}
+			moves := totals[t] - counts[types[t]][c] + rest
+			if result < 0 || moves < result {
+				result = moves
+			}
+		}
+		memo[key] = result
+		return result
+	}
+
+	space := make([]int, compartments)
+	for c := range space {
+		space[c] = size
+	}
+	moves := best(0, space)
+	if moves < 0 {
+		return "", 0, fmt.Errorf("the item types cannot be split into %d compartments of %d", compartments, size)
+	}
+
+	// follow the optimal choices again to get the assignment
+	assigned := make(map[byte]int)
+	remaining := moves
+	for t, item := range types {
+		for c := range space {
+			if space[c] < totals[t] {
+				continue
+			}
+			space[c] -= totals[t]
+			rest := best(t+1, space)
+			if rest >= 0 && totals[t]-counts[item][c]+rest == remaining {
+				assigned[item] = c
+				remaining = rest
+				break
+			}
+			space[c] += totals[t]
+		}
+	}
+
+	incoming := make([][]byte, compartments)
+	for i := 0; i < len(rucksack); i++ {
+		item := rucksack[i]
+		if assigned[item] != i/size {
+			incoming[assigned[item]] = append(incoming[assigned[item]], item)
+		}
+	}
+
+	fixed := []byte(rucksack)
+	for i := range fixed {
+		c := i / size
+		if assigned[rucksack[i]] != c {
+			fixed[i] = incoming[c][0]
+			incoming[c] = incoming[c][1:]
+		}
+	}
+	return string(fixed), moves, nil
+}
+
+func planRepacks(rucksacks []string, compartments int) []repackPlan {
+	plans := make([]repackPlan, len(rucksacks))
+	for i, rucksack := range rucksacks {
+		fixed, moves, err := planRepack(rucksack, compartments)
+		plans[i] = repackPlan{rucksack, fixed, moves, err}
+	}
+	return plans
+}
+
+// printRepacks prints every rucksack after repacking, one per line like
+// the input, keeping the rucksacks that cannot be repacked as they are.
+// The compartments and moves of every rucksack go to stderr, so that the
+// list can be saved as an input.
+func printRepacks(plans []repackPlan, compartments int) {
+	totalMoves, infeasible := 0, 0
+	for i, plan := range plans {
+		if plan.err != nil {
+			infeasible++
+			fmt.Println(plan.rucksack)
+			fmt.Fprintf(os.Stderr, "Rucksack %d: infeasible, %v\n", i+1, plan.err)
+			continue
+		}
+		totalMoves += plan.moves
+		fmt.Println(plan.fixed)
+		size := len(plan.fixed) / compartments
+		parts := make([]string, compartments)
+		for c := range parts {
+			parts[c] = plan.fixed[c*size : (c+1)*size]
+		}
+		fmt.Fprintf(os.Stderr, "Rucksack %d: %s (%d moves)\n", i+1, strings.Join(parts, " "), plan.moves)
+	}
+
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Total moves:", totalMoves)
+	if infeasible > 0 {
+		fmt.Fprintln(os.Stderr, "Infeasible rucksacks:", infeasible)
+	}
+}
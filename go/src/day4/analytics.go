@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"interval"
+	"strings"
+)
+
+func toInterval(r rangePair) interval.Interval {
+	return interval.Interval{Start: r.start, End: r.end}
+}
+
+// campAssignments returns the assignment of every Elf in the camp,
+// the Elves of a group one after the other
+func campAssignments(groups [][]rangePair) []interval.Interval {
+	assignments := []interval.Interval{}
+	for _, group := range groups {
+		for _, a := range group {
+			assignments = append(assignments, toInterval(a))
+		}
+	}
+	return assignments
+}
+
+func formatIntervals(intervals []interval.Interval) string {
+	parts := make([]string, len(intervals))
+	for i, a := range intervals {
+		if a.Start == a.End {
+			parts[i] = fmt.Sprint(a.Start)
+		} else {
+			parts[i] = fmt.Sprintf("%d-%d", a.Start, a.End)
+		}
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// printAnalytics looks at the whole camp at once instead of pair by pair
+func printAnalytics(groups [][]rangePair) {
+	assignments := campAssignments(groups)
+	span, ok := interval.Span(assignments)
+	if !ok {
+		fmt.Println("No assignments")
+		return
+	}
+	// section IDs start at 1, so anything before the first assignment is a gap too
+	camp := interval.Interval{Start: 1, End: span.End}
+
+	fmt.Println("Elves:", len(assignments))
+	fmt.Println("Union:", formatIntervals(interval.Merge(assignments)))
+	fmt.Printf("Coverage: %d of %d sections\n", interval.Coverage(assignments), camp.Len())
+	fmt.Println("Cleaned by nobody:", formatIntervals(interval.Gaps(assignments, camp)))
+
+	most, stretches := interval.MostContested(assignments)
+	fmt.Printf("Most contested: %s, cleaned by %d Elves\n", formatIntervals(stretches), most)
+
+	fmt.Println()
+	fmt.Println("Heat per section:")
+	heat := interval.Sweep(assignments)
+	for _, gap := range interval.Gaps(assignments, interval.Interval{Start: 1, End: span.Start - 1}) {
+		heat = append([]interval.Heat{{Interval: gap}}, heat...)
+	}
+	for _, h := range heat {
+		fmt.Printf("%7s %4d | %s\n", formatIntervals([]interval.Interval{h.Interval}), h.Count, strings.Repeat("#", h.Count*60/most))
+	}
+}
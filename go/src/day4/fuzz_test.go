@@ -0,0 +1,177 @@
+package main
+
+import (
+	"errors"
+	"interval"
+	"os"
+	"parsing"
+	"strings"
+	"testing"
+)
+
+const example = `2-4,6-8
+2-3,4-5
+5-7,7-9
+2-8,3-7
+6-6,4-6
+2-6,4-8
+`
+
+func addInputs(f *testing.F) {
+	f.Add(example)
+	f.Add("1-10,2-3,2-9,4-12\n5-5,5-5,5-5\n")
+	if input, err := os.ReadFile("../../input/day4.txt"); err == nil {
+		f.Add(string(input))
+	}
+}
+
+// readGroups parses the input like main does, every error must say
+// where in the input the problem is
+func readGroups(t *testing.T, data string) ([][]rangePair, bool) {
+	groups, err := parseGroups(strings.Split(strings.TrimSuffix(data, "\n"), "\n"))
+	if err != nil {
+		var parseErr *parsing.Error
+		if !errors.As(err, &parseErr) || parseErr.Line < 1 {
+			t.Fatalf("error without a line: %v", err)
+		}
+		return nil, false
+	}
+	return groups, true
+}
+
+// checkForest returns the number of nodes in the forest, making sure
+// every assignment lies in its parent
+func checkForest(t *testing.T, nodes []*containmentNode) int {
+	count := len(nodes)
+	for _, node := range nodes {
+		for _, child := range node.children {
+			if !contains(node.sections, child.sections) {
+				t.Fatalf("Elf %d is not inside its parent Elf %d", child.elf, node.elf)
+			}
+		}
+		count += checkForest(t, node.children)
+	}
+	return count
+}
+
+// FuzzGroups checks both parts and the containment forest of every group
+func FuzzGroups(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data string) {
+		groups, ok := readGroups(t, data)
+		if !ok {
+			return
+		}
+
+		contained, overlapping := getSolution(groups, 1), getSolution(groups, 2)
+		if contained < 0 || contained > len(groups) || overlapping < 0 || overlapping > len(groups) {
+			t.Fatalf("%d contained and %d overlapping of %d groups", contained, overlapping, len(groups))
+		}
+		if data == example && (contained != 2 || overlapping != 4) {
+			t.Fatalf("example: %d contained and %d overlapping, expected 2 and 4", contained, overlapping)
+		}
+		for _, group := range groups {
+			// a pair where one contains the other always overlaps
+			if len(group) == 2 && anyContains(group) && !allShare(group) {
+				t.Fatalf("%v is contained but does not overlap", group)
+			}
+			if n := checkForest(t, containmentForest(group)); n != len(group) {
+				t.Fatalf("the forest of %v has %d nodes", group, n)
+			}
+		}
+	})
+}
+
+// FuzzReassign checks that the new assignments of every group cover the
+// same sections as before, without any section cleaned twice, and that
+// every Elf keeps a part of their own assignment. The new assignments
+// must read back as an input.
+func FuzzReassign(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data string) {
+		groups, ok := readGroups(t, data)
+		if !ok {
+			return
+		}
+
+		assignments := campAssignments(groups)
+		reassigned := []interval.Interval{}
+		next := 0
+		for _, group := range groups {
+			before := assignments[next : next+len(group)]
+			next += len(group)
+			after := reassign(before)
+			reassigned = append(reassigned, after...)
+
+			kept := nonEmpty(after)
+			for i, a := range after {
+				if a.Len() > 0 && !before[i].Contains(a) {
+					t.Fatalf("%v got %v outside of their assignment", before[i], a)
+				}
+			}
+			for i := range kept {
+				for j := range kept[:i] {
+					if kept[i].Overlaps(kept[j]) {
+						t.Fatalf("%v and %v overlap after reassigning %v", kept[i], kept[j], before)
+					}
+				}
+			}
+			if formatIntervals(interval.Merge(kept)) != formatIntervals(interval.Merge(before)) {
+				t.Fatalf("reassigning %v covers %v", before, after)
+			}
+		}
+
+		// the output of -reassign is an input again
+		lines := reassignmentLines(groups, reassigned)
+		again, err := parseGroups(lines)
+		if err != nil {
+			t.Fatalf("cannot read back the reassignment: %v", err)
+		}
+		for i, a := range campAssignments(again) {
+			if a != reassigned[i] && (a.Len() > 0 || reassigned[i].Len() > 0) {
+				t.Fatalf("Elf %d was reassigned %v but reads back as %v", i+1, reassigned[i], a)
+			}
+		}
+	})
+}
+
+// FuzzQuery compares the answers of the interval tree with a scan of
+// every assignment
+func FuzzQuery(f *testing.F) {
+	f.Add(example, "5")
+	f.Add(example, "3-6")
+	f.Add(example, "7-7")
+	f.Fuzz(func(t *testing.T, data string, query string) {
+		groups, ok := readGroups(t, data)
+		if !ok {
+			return
+		}
+		sections, err := parseQuery(query)
+		if err != nil {
+			return
+		}
+		if sections.Len() == 0 {
+			t.Fatalf("query %q reads as the empty %v", query, sections)
+		}
+
+		tree, elves := buildIndex(groups)
+		found := tree.Intersecting(sections)
+		if sections.Len() == 1 {
+			found = tree.Stab(sections.Start)
+		}
+		want := 0
+		for _, a := range campAssignments(groups) {
+			if a.Overlaps(sections) {
+				want++
+			}
+		}
+		if len(found) != want {
+			t.Fatalf("query %v found %d Elves, expected %d", sections, len(found), want)
+		}
+		for _, e := range found {
+			if e.ID < 0 || e.ID >= len(elves) {
+				t.Fatalf("unknown Elf %d", e.ID)
+			}
+		}
+	})
+}
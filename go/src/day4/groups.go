@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"parsing"
+	"sort"
+	"strings"
+)
+
+// parseGroup reads the assignments of a group of Elves, a pair in the
+// puzzle but a line may hold any number of comma separated ranges
+func parseGroup(line string) ([]rangePair, error) {
+	group := []rangePair{}
+	column := 0
+	for _, section := range strings.Split(line, ",") {
+		a, err := parseAssignment(section)
+		if err != nil {
+			// the column is counted from the start of the line
+			if e, ok := err.(*parsing.Error); ok {
+				e.Column += column
+			}
+			return nil, err
+		}
+		group = append(group, a)
+		column += len(section) + 1
+	}
+	return group, nil
+}
+
+// parseAssignment reads the range of an Elf. Only assignment lists hold
+// the empty range, which -reassign writes for an Elf with nothing left
+// to clean.
+func parseAssignment(s string) (rangePair, error) {
+	if s == formatRange(unassigned) {
+		return rangePair{unassigned.Start, unassigned.End}, nil
+	}
+	return parseRangePair(s)
+}
+
+// parseGroups reads a group per line
+func parseGroups(input []string) ([][]rangePair, error) {
+	groups := make([][]rangePair, len(input))
+	for i, line := range input {
+		group, err := parseGroup(line)
+		if err != nil {
+			return nil, parsing.AtLine(err, i+1)
+		}
+		groups[i] = group
+	}
+	return groups, nil
+}
+
+func length(a rangePair) int {
+	return a.end - a.start + 1
+}
+
+// contains reports whether b lies inside a, an Elf without any
+// section neither contains another Elf nor lies inside one
+func contains(a rangePair, b rangePair) bool {
+	return length(a) > 0 && length(b) > 0 && a.start <= b.start && a.end >= b.end
+}
+
+// anyContains reports whether an assignment of the group fully contains
+// another one, for a pair this is the question of part 1
+func anyContains(group []rangePair) bool {
+	for i := range group {
+		for j := range group {
+			if i != j && contains(group[i], group[j]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sharedSections returns the sections every Elf of the group cleans,
+// start is past end when there are none
+func sharedSections(group []rangePair) rangePair {
+	shared := group[0]
+	for _, a := range group[1:] {
+		if a.start > shared.start {
+			shared.start = a.start
+		}
+		if a.end < shared.end {
+			shared.end = a.end
+		}
+	}
+	return shared
+}
+
+// allShare reports whether there is a section every Elf of the group
+// cleans, for a pair this is the question of part 2
+func allShare(group []rangePair) bool {
+	shared := sharedSections(group)
+	return shared.start <= shared.end
+}
+
+// containmentNode is an assignment with the assignments nested inside it
+type containmentNode struct {
+	elf      int
+	sections rangePair
+	children []*containmentNode
+}
+
+// containmentForest nests every assignment of the group in the smallest
+// assignment containing it. Equal assignments nest in the order of the
+// line, so the forest never has cycles. Assignments can overlap without
+// nesting, so an assignment may lie in several others that are not
+// nested themselves; it then goes under the shortest of them.
+func containmentForest(group []rangePair) []*containmentNode {
+	nodes := make([]*containmentNode, len(group))
+	for i, a := range group {
+		nodes[i] = &containmentNode{elf: i + 1, sections: a}
+	}
+
+	// sorting by start, and by end backwards, puts every assignment after
+	// the ones containing it, so its parent has always been placed already
+	order := make([]int, len(group))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := group[order[i]], group[order[j]]
+		if a.start != b.start {
+			return a.start < b.start
+		}
+		return a.end > b.end
+	})
+
+	roots := []*containmentNode{}
+	for k, i := range order {
+		var parent *containmentNode
+		for _, j := range order[:k] {
+			if !contains(group[j], group[i]) {
+				continue
+			}
+			if parent == nil || length(group[j]) <= length(parent.sections) {
+				parent = nodes[j]
+			}
+		}
+		if parent == nil {
+			roots = append(roots, nodes[i])
+		} else {
+			parent.children = append(parent.children, nodes[i])
+		}
+	}
+	return roots
+}
+
+func printForest(nodes []*containmentNode, depth int) {
+	for _, node := range nodes {
+		fmt.Printf("%sElf %d: %d-%d\n", strings.Repeat("  ", depth+1), node.elf, node.sections.start, node.sections.end)
+		printForest(node.children, depth+1)
+	}
+}
+
+// printGroups answers both questions for every group and shows
+// which assignments nest inside which
+func printGroups(groups [][]rangePair) {
+	contained, shared := 0, 0
+	for i, group := range groups {
+		fmt.Printf("Group %d: %d Elves", i+1, len(group))
+		if anyContains(group) {
+			contained++
+			fmt.Print(", nested")
+		}
+		if allShare(group) {
+			shared++
+			common := sharedSections(group)
+			fmt.Printf(", all clean %d-%d", common.start, common.end)
+		}
+		fmt.Println()
+		printForest(containmentForest(group), 0)
+	}
+
+	fmt.Println()
+	fmt.Println("Groups with an assignment containing another: ", contained)
+	fmt.Println("Groups sharing a section: ", shared)
+}
@@ -86,12 +86,11 @@ In how many assignment pairs do the ranges overlap?
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
-	"strings"
+	"parsing"
 )
 
 type rangePair struct {
@@ -99,33 +98,33 @@ type rangePair struct {
 	end   int
 }
 
-func parseRangePair(s string) rangePair {
-	parsed := strings.Split(s, "-")
-	start, _ := strconv.Atoi(parsed[0])
-	end, _ := strconv.Atoi(parsed[1])
-	return rangePair{start, end}
-}
-
-func hasOverlap(a rangePair, b rangePair) bool {
-	return a.start <= b.end && a.end >= b.start
-}
+// maxSection bounds the section numbers, far above any camp but low
+// enough that lengths and the interval tree can never overflow
+const maxSection = 1 << 40
 
-func isFullyContained(a rangePair, b rangePair) bool {
-	bInA := a.start <= b.start && a.end >= b.end
-	aInB := a.start >= b.start && a.end <= b.end
-	return aInB || bInA
+func parseRangePair(s string) (rangePair, error) {
+	var start, end int
+	if err := parsing.Scan(s, "{n}-{n}", &start, &end); err != nil {
+		return rangePair{}, err
+	}
+	if start < -maxSection || end > maxSection {
+		return rangePair{}, &parsing.Error{Column: 1, Err: fmt.Errorf("range %s is out of bounds, sections go up to %d", s, maxSection)}
+	}
+	if end < start {
+		return rangePair{}, &parsing.Error{Column: 1, Err: fmt.Errorf("range %s ends before it starts", s)}
+	}
+	return rangePair{start, end}, nil
 }
 
-func getSolution(input []string, part int) int {
+// getSolution counts the groups where an assignment contains another for
+// part 1 and the groups where every Elf cleans a common section for part 2.
+// With two Elves per line these are the puzzle's pair questions.
+func getSolution(groups [][]rangePair, part int) int {
 	totalSections := 0
-	for _, line := range input {
-		sections := strings.Split(line, ",")
-		section1 := parseRangePair(sections[0])
-		section2 := parseRangePair(sections[1])
-
-		if part == 1 && isFullyContained(section1, section2) {
+	for _, group := range groups {
+		if part == 1 && anyContains(group) {
 			totalSections++
-		} else if part == 2 && hasOverlap(section1, section2) {
+		} else if part == 2 && allShare(group) {
 			totalSections++
 		}
 	}
@@ -134,19 +133,72 @@ func getSolution(input []string, part int) int {
 }
 
 func main() {
-	file, err := os.Open("../../input/day4.txt")
+	inputFile := flag.String("input", "../../input/day4.txt", "section assignments")
+	analytics := flag.Bool("analytics", false, "report coverage and contested sections for the whole camp")
+	draw := flag.Bool("draw", false, "draw the pairs like the puzzle illustration")
+	filter := flag.String("filter", "all", "only draw pairs that are contained, overlapping, disjoint or overlap at all")
+	window := flag.String("window", "", "only draw sections in this range, like 10-40")
+	reassignMode := flag.String("reassign", "", "print assignments without overlaps, fixing each pair or the whole camp (pair or camp)")
+	nesting := flag.Bool("groups", false, "show how the assignments of every group nest inside each other")
+	query := flag.Bool("query", false, "answer queries for sections (4711) or ranges (300-450) read from stdin")
+	flag.Parse()
+
+	file, err := os.Open(*inputFile)
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	var input []string
-	for scanner.Scan() {
-		line := scanner.Text()
-		input = append(input, line)
+	input, err := parsing.ReadLines(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+	groups, err := parseGroups(input)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *query {
+		tree, elves := buildIndex(groups)
+		if err := answerQueries(tree, elves, os.Stdin, os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *draw {
+		var sections rangePair
+		if *window != "" {
+			sections, err = parseRangePair(*window)
+			if err != nil {
+				log.Fatal("window: ", err)
+			}
+		}
+		drawing, err := renderPairs(groups, *filter, toInterval(sections))
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Print(drawing)
+		return
+	}
+
+	if *reassignMode != "" {
+		if err := printReassignment(groups, *reassignMode); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *nesting {
+		printGroups(groups)
+		return
+	}
+
+	if *analytics {
+		printAnalytics(groups)
+		return
 	}
 
-	fmt.Println("Number of fully contained sections: ", getSolution(input, 1))
-	fmt.Println("Number of overlapping sections: ", getSolution(input, 2))
+	fmt.Println("Number of fully contained sections: ", getSolution(groups, 1))
+	fmt.Println("Number of overlapping sections: ", getSolution(groups, 2))
 }
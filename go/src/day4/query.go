@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"interval"
+	"io"
+	"parsing"
+	"sort"
+	"strings"
+)
+
+// elfRef tells where an Elf of the index comes from
+type elfRef struct {
+	group int
+	elf   int
+}
+
+// buildIndex puts every assignment of the camp in an interval tree,
+// the ID of an assignment is its position in the returned Elves
+func buildIndex(groups [][]rangePair) (*interval.Tree, []elfRef) {
+	tree := &interval.Tree{}
+	elves := []elfRef{}
+	for i, group := range groups {
+		for j, a := range group {
+			tree.Insert(toInterval(a), len(elves))
+			elves = append(elves, elfRef{i + 1, j + 1})
+		}
+	}
+	return tree, elves
+}
+
+// parseQuery reads a section, like 4711, or a range of sections, like 300-450
+func parseQuery(query string) (interval.Interval, error) {
+	var start int
+	if err := parsing.Scan(query, "{n}", &start); err == nil {
+		return interval.Interval{Start: start, End: start}, nil
+	}
+	sections, err := parseRangePair(query)
+	if err != nil {
+		return interval.Interval{}, err
+	}
+	return toInterval(sections), nil
+}
+
+// answerQueries reads a query per line and lists the Elves cleaning the
+// section, or any section of the range. Bad queries are reported and skipped.
+func answerQueries(tree *interval.Tree, elves []elfRef, r io.Reader, w io.Writer) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		query := strings.TrimSpace(scanner.Text())
+		if query == "" {
+			continue
+		}
+		sections, err := parseQuery(query)
+		if err != nil {
+			fmt.Fprintln(w, err)
+			continue
+		}
+
+		var found []interval.Entry
+		if sections.Len() == 1 {
+			found = tree.Stab(sections.Start)
+			fmt.Fprintf(w, "Section %d: %d Elves\n", sections.Start, len(found))
+		} else {
+			found = tree.Intersecting(sections)
+			fmt.Fprintf(w, "Sections %d-%d: %d Elves\n", sections.Start, sections.End, len(found))
+		}
+		sort.Slice(found, func(i, j int) bool {
+			return found[i].ID < found[j].ID
+		})
+		for _, e := range found {
+			ref := elves[e.ID]
+			fmt.Fprintf(w, "  group %d, Elf %d: %d-%d\n", ref.group, ref.elf, e.Start, e.End)
+		}
+	}
+	return scanner.Err()
+}
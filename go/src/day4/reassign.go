@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"interval"
+	"os"
+	"sort"
+	"strings"
+)
+
+// unassigned is the empty range given to an Elf with nothing left to
+// clean. It is written 1-0, which overlaps no section since IDs start at 1.
+var unassigned = interval.Interval{Start: 1, End: 0}
+
+// coverable reports whether the Elves that are not used yet can cover
+// from..to between them, each with a part of their own assignment.
+// byStart holds the indexes of the Elves sorted by the start of their
+// assignment, which makes the classic furthest reach greedy a single pass.
+func coverable(assignments []interval.Interval, byStart []int, used []bool, from int, to int) bool {
+	pos, i := from, 0
+	for pos <= to {
+		reach := pos - 1
+		for i < len(byStart) && assignments[byStart[i]].Start <= pos {
+			if a := assignments[byStart[i]]; !used[byStart[i]] && a.End > reach {
+				reach = a.End
+			}
+			i++
+		}
+		if reach < pos {
+			return false
+		}
+		pos = reach + 1
+	}
+	return true
+}
+
+// reassign makes the assignments disjoint while every section that was
+// assigned stays assigned, changing the lengths of the ranges as little
+// as possible.
+//
+// Every Elf keeps a part of their own assignment or nothing at all. The
+// lengths then drop by exactly the sections that were cleaned twice, which
+// no solution can beat, so shifting a range never pays off. Among these
+// solutions we try to keep many Elves busy: going from left to right, the
+// next section goes to the free Elf whose assignment ends first, for as few
+// sections as still lets the other free Elves cover the rest.
+func reassign(assignments []interval.Interval) []interval.Interval {
+	result := make([]interval.Interval, len(assignments))
+	used := make([]bool, len(assignments))
+	byStart := make([]int, len(assignments))
+	for i, a := range assignments {
+		result[i] = unassigned
+		used[i] = a.Len() == 0
+		byStart[i] = i
+	}
+	sort.Slice(byStart, func(i, j int) bool {
+		return assignments[byStart[i]].Start < assignments[byStart[j]].Start
+	})
+
+	for _, part := range interval.Merge(assignments) {
+		for p := part.Start; p <= part.End; {
+			candidates := []int{}
+			for i, a := range assignments {
+				if !used[i] && a.Start <= p && a.End >= p {
+					candidates = append(candidates, i)
+				}
+			}
+			sort.Slice(candidates, func(i, j int) bool {
+				return assignments[candidates[i]].End < assignments[candidates[j]].End
+			})
+
+			for _, c := range candidates {
+				end := assignments[c].End
+				if end > part.End {
+					end = part.End
+				}
+				used[c] = true
+				if !coverable(assignments, byStart, used, end+1, part.End) {
+					used[c] = false
+					continue
+				}
+				// the rest only gets easier to cover the later it starts,
+				// so the shortest block that works is found by bisection
+				n := sort.Search(end-p+1, func(n int) bool {
+					return coverable(assignments, byStart, used, p+n+1, part.End)
+				})
+				result[c] = interval.Interval{Start: p, End: p + n}
+				p += n + 1
+				break
+			}
+		}
+	}
+	return result
+}
+
+func formatRange(a interval.Interval) string {
+	if a.Len() == 0 {
+		a = unassigned
+	}
+	return fmt.Sprintf("%d-%d", a.Start, a.End)
+}
+
+// reassignmentLines writes the new assignments in the puzzle format,
+// a line per group, which parseGroups reads back
+func reassignmentLines(groups [][]rangePair, reassigned []interval.Interval) []string {
+	lines := make([]string, len(groups))
+	next := 0
+	for i, group := range groups {
+		ranges := make([]string, len(group))
+		for j := range group {
+			ranges[j] = formatRange(reassigned[next])
+			next++
+		}
+		lines[i] = strings.Join(ranges, ",")
+	}
+	return lines
+}
+
+// printReassignment writes the new assignment list in the puzzle format,
+// fixing each pair, or group, on its own or the whole camp at once
+func printReassignment(groups [][]rangePair, mode string) error {
+	assignments := campAssignments(groups)
+
+	reassigned := make([]interval.Interval, 0, len(assignments))
+	switch mode {
+	case "pair":
+		next := 0
+		for _, group := range groups {
+			reassigned = append(reassigned, reassign(assignments[next:next+len(group)])...)
+			next += len(group)
+		}
+	case "camp":
+		reassigned = reassign(assignments)
+	default:
+		return fmt.Errorf("unknown reassignment mode %q", mode)
+	}
+
+	change, idle := 0, 0
+	for i, a := range reassigned {
+		change += assignments[i].Len() - a.Len()
+		if a.Len() == 0 && assignments[i].Len() > 0 {
+			idle++
+		}
+	}
+
+	for _, line := range reassignmentLines(groups, reassigned) {
+		fmt.Println(line)
+	}
+
+	// the summary goes to stderr so that the list can be saved as an input
+	fmt.Fprintln(os.Stderr, "Sections taken off:", change)
+	fmt.Fprintln(os.Stderr, "Elves with nothing left to clean:", idle)
+	fmt.Fprintln(os.Stderr, "Sections covered:", formatIntervals(interval.Merge(nonEmpty(reassigned))))
+	return nil
+}
+
+func nonEmpty(intervals []interval.Interval) []interval.Interval {
+	kept := []interval.Interval{}
+	for _, a := range intervals {
+		if a.Len() > 0 {
+			kept = append(kept, a)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"fmt"
+	"interval"
+	"strings"
+)
+
+// maxColumns is how wide a drawing gets before sections are squeezed
+// together, a column then stands for several sections
+const maxColumns = 100
+
+// pairKind is how the assignments of a pair, or a bigger group,
+// relate to each other
+func pairKind(group []rangePair) string {
+	if anyContains(group) {
+		return "contained"
+	} else if allShare(group) {
+		return "overlapping"
+	}
+	return "disjoint"
+}
+
+// pairFilters are the predicates a drawing can be limited to
+var pairFilters = map[string]func(group []rangePair) bool{
+	"all":         func(group []rangePair) bool { return true },
+	"overlap":     allShare,
+	"contained":   anyContains,
+	"overlapping": func(group []rangePair) bool { return allShare(group) && !anyContains(group) },
+	"disjoint":    func(group []rangePair) bool { return !allShare(group) },
+}
+
+// axis maps the columns of the drawing to section IDs
+type axis struct {
+	window interval.Interval
+	scale  int
+}
+
+func newAxis(window interval.Interval, columns int) axis {
+	scale := (window.Len() + columns - 1) / columns
+	if scale < 1 {
+		scale = 1
+	}
+	return axis{window, scale}
+}
+
+func (ax axis) columns() int {
+	return (ax.window.Len() + ax.scale - 1) / ax.scale
+}
+
+// column returns the sections shown in a column
+func (ax axis) column(c int) interval.Interval {
+	start := ax.window.Start + c*ax.scale
+	end := start + ax.scale - 1
+	if end > ax.window.End {
+		end = ax.window.End
+	}
+	return interval.Interval{Start: start, End: end}
+}
+
+// covered counts the sections of a column inside the assignment
+func covered(column interval.Interval, a interval.Interval) int {
+	start, end := column.Start, column.End
+	if a.Start > start {
+		start = a.Start
+	}
+	if a.End < end {
+		end = a.End
+	}
+	return interval.Interval{Start: start, End: end}.Len()
+}
+
+// digit returns the digit of the section at a place, 1 for the units
+// and 10 for the tens. Negative sections show the digits of their
+// absolute value, so that every column stays a single character.
+func digit(section int, place int) byte {
+	if section < 0 {
+		section = -section
+	}
+	return byte('0' + section/place%10)
+}
+
+// drawAssignment draws a row like the puzzle does, .234..... for 2-4.
+// When a column holds several sections it shows # if the assignment
+// covers all of them and + if it covers some.
+func drawAssignment(ax axis, a interval.Interval) string {
+	var b strings.Builder
+	for c := 0; c < ax.columns(); c++ {
+		column := ax.column(c)
+		n := covered(column, a)
+		switch {
+		case n == 0:
+			b.WriteByte('.')
+		case ax.scale == 1:
+			b.WriteByte(digit(column.Start, 1))
+		case n == column.Len():
+			b.WriteByte('#')
+		default:
+			b.WriteByte('+')
+		}
+	}
+	return b.String()
+}
+
+// drawOverlap marks the columns where all the assignments meet
+func drawOverlap(ax axis, shared interval.Interval) string {
+	var row strings.Builder
+	for c := 0; c < ax.columns(); c++ {
+		if covered(ax.column(c), shared) > 0 {
+			row.WriteByte('^')
+		} else {
+			row.WriteByte(' ')
+		}
+	}
+	return strings.TrimRight(row.String(), " ")
+}
+
+// drawRuler labels the axis, with the tens above the units
+// when every column is a single section
+func drawRuler(ax axis) []string {
+	if ax.scale > 1 {
+		return []string{fmt.Sprintf("sections %d-%d, %d per column", ax.window.Start, ax.window.End, ax.scale)}
+	}
+
+	var tens, units strings.Builder
+	for c := 0; c < ax.columns(); c++ {
+		s := ax.column(c).Start
+		units.WriteByte(digit(s, 1))
+		if s%10 == 0 || c == 0 {
+			tens.WriteByte(digit(s, 10))
+		} else {
+			tens.WriteByte(' ')
+		}
+	}
+	return []string{tens.String(), units.String()}
+}
+
+// renderPairs draws the pairs matching the filter on a shared axis.
+// A zero window means the whole span of the drawn pairs.
+func renderPairs(groups [][]rangePair, filter string, window interval.Interval) (string, error) {
+	keep, ok := pairFilters[filter]
+	if !ok {
+		return "", fmt.Errorf("unknown filter %q", filter)
+	}
+
+	type pair struct {
+		line  int
+		group []rangePair
+	}
+	pairs := []pair{}
+	assignments := []interval.Interval{}
+	for i, group := range groups {
+		if !keep(group) {
+			continue
+		}
+		inWindow := window == (interval.Interval{})
+		for _, a := range group {
+			inWindow = inWindow || window.Overlaps(toInterval(a))
+		}
+		if !inWindow {
+			continue
+		}
+		pairs = append(pairs, pair{i + 1, group})
+		for _, a := range group {
+			assignments = append(assignments, toInterval(a))
+		}
+	}
+	if len(pairs) == 0 {
+		return "No pairs to draw\n", nil
+	}
+
+	if window == (interval.Interval{}) {
+		// start at section 1 like the puzzle, unless there are sections before it
+		span, found := interval.Span(assignments)
+		if !found {
+			span = interval.Interval{Start: 1, End: 1}
+		}
+		window = interval.Interval{Start: min(1, span.Start), End: max(1, span.End)}
+	}
+	ax := newAxis(window, maxColumns)
+
+	var out strings.Builder
+	for _, line := range drawRuler(ax) {
+		out.WriteString(line + "\n")
+	}
+	for _, p := range pairs {
+		fmt.Fprintf(&out, "\nPair %d: %s\n", p.line, pairKind(p.group))
+		for _, a := range p.group {
+			fmt.Fprintf(&out, "%s  %d-%d\n", drawAssignment(ax, toInterval(a)), a.start, a.end)
+		}
+		if overlap := drawOverlap(ax, toInterval(sharedSections(p.group))); overlap != "" {
+			out.WriteString(overlap + "\n")
+		}
+	}
+	return out.String(), nil
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"interval"
+	"strings"
+	"testing"
+)
+
+// TestDrawNegative draws sections on both sides of zero, every column
+// must stay a single digit lined up with the ruler
+func TestDrawNegative(t *testing.T) {
+	groups, err := parseGroups([]string{"-12--8,-10-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drawing, err := renderPairs(groups, "all", interval.Interval{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(drawing, "\n")
+	want := []string{
+		"1 1         0 ",
+		"21098765432101",
+		"",
+		"Pair 1: overlapping",
+		"21098.........  -12--8",
+		"..098765432101  -10-1",
+		"  ^^^",
+		"",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("drawn as\n%s\nexpected\n%s", drawing, strings.Join(want, "\n"))
+	}
+}
+
+func TestDigit(t *testing.T) {
+	tests := []struct {
+		section int
+		place   int
+		want    byte
+	}{
+		{7, 1, '7'},
+		{42, 10, '4'},
+		{-3, 1, '3'},
+		{-12, 1, '2'},
+		{-12, 10, '1'},
+		{-105, 10, '0'},
+	}
+	for _, tt := range tests {
+		if got := digit(tt.section, tt.place); got != tt.want {
+			t.Errorf("digit of %d at %d is %q, expected %q", tt.section, tt.place, got, tt.want)
+		}
+	}
+}
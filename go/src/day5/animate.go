@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+const (
+	ansiClear      = "\033[H\033[2J"
+	ansiHideCursor = "\033[?25l"
+	ansiShowCursor = "\033[?25h"
+	ansiLifted     = "\033[1;33m"
+	ansiPlaced     = "\033[1;32m"
+	ansiReset      = "\033[0m"
+)
+
+// highlight marks the top crates of a stack in a frame
+type highlight struct {
+	stack    int
+	quantity int
+	color    string
+}
+
+// player keeps the playback state that the keys can change
+type player struct {
+	delay  time.Duration
+	paused bool
+	keys   chan byte
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// renderStacks draws the stacks the same way the puzzle input does.
+//
+// Crates covered by the highlight are wrapped in its color,
+// unless color is false, in which case no escape codes are used at all.
+func renderStacks(stacks []Stack, mark highlight, color bool) string {
+	height := 0
+	for i := range stacks {
+		if stacks[i].Size() > height {
+			height = stacks[i].Size()
+		}
+	}
+
+	var b strings.Builder
+	for row := height - 1; row >= 0; row-- {
+		cells := make([]string, len(stacks))
+		for i := range stacks {
+			items := stacks[i].Values()
+			if row >= len(items) {
+				cells[i] = "   "
+				continue
+			}
+			cell := fmt.Sprintf("[%s]", items[row])
+			if color && i == mark.stack && row >= len(items)-mark.quantity {
+				cell = mark.color + cell + ansiReset
+			}
+			cells[i] = cell
+		}
+		b.WriteString(strings.TrimRight(strings.Join(cells, " "), " "))
+		b.WriteString("\n")
+	}
+
+	labels := make([]string, len(stacks))
+	for i := range stacks {
+		labels[i] = fmt.Sprintf(" %d ", i+1)
+	}
+	b.WriteString(strings.Join(labels, " "))
+	b.WriteString("\n")
+	return b.String()
+}
+
+// enableRawInput switches the terminal to unbuffered input without echo
+// so single key presses reach us, and returns a function restoring it
+func enableRawInput() (func(), error) {
+	cmd := exec.Command("stty", "-g")
+	cmd.Stdin = os.Stdin
+	state, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+
+	cmd = exec.Command("stty", "-icanon", "-echo", "min", "1")
+	cmd.Stdin = os.Stdin
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+
+	return func() {
+		cmd := exec.Command("stty", strings.TrimSpace(string(state)))
+		cmd.Stdin = os.Stdin
+		cmd.Run()
+	}, nil
+}
+
+func readKeys(keys chan<- byte) {
+	buf := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		if n == 1 {
+			keys <- buf[0]
+		}
+	}
+}
+
+// wait blocks until the next frame is due.
+//
+// Space pauses and resumes, n steps a single frame while paused,
+// + and - change the speed and q stops the playback (wait returns false).
+func (p *player) wait() bool {
+	timer := time.NewTimer(p.delay)
+	defer timer.Stop()
+
+	for {
+		var tick <-chan time.Time
+		if !p.paused {
+			tick = timer.C
+		}
+
+		select {
+		case <-tick:
+			return true
+		case key, ok := <-p.keys:
+			if !ok {
+				// no more input, keep playing on the timer
+				p.keys = nil
+				p.paused = false
+				continue
+			}
+			switch key {
+			case ' ':
+				p.paused = !p.paused
+				if !p.paused {
+					return true
+				}
+			case 'n':
+				if p.paused {
+					return true
+				}
+			case '+':
+				if p.delay > time.Millisecond {
+					p.delay /= 2
+				}
+			case '-':
+				p.delay *= 2
+			case 'q':
+				return false
+			}
+		}
+	}
+}
+
+func showFrame(title string, stacks []Stack, mark highlight, tty bool) {
+	if tty {
+		fmt.Print(ansiClear)
+		fmt.Println(title)
+		fmt.Println()
+		fmt.Print(renderStacks(stacks, mark, true))
+		fmt.Println()
+		fmt.Println("space: pause/resume  n: step  +/-: speed  q: quit")
+		return
+	}
+	fmt.Println(title)
+	fmt.Print(renderStacks(stacks, mark, false))
+	fmt.Println()
+}
+
+// animate plays the rearrangement back move by move.
+//
+// Every move is shown twice, first with the crates about to be lifted
+// highlighted on the source stack, then with them on the destination.
+// When stdout is not a terminal the frames are simply printed one after
+// another without any escape codes or delays.
+func animate(rearrangements []string, stackInput []string, part int, delay time.Duration) {
+	stacks := buildStacks(stackInput)
+	tty := isTerminal(os.Stdout)
+	p := &player{delay: delay}
+
+	if tty {
+		if isTerminal(os.Stdin) {
+			if restore, err := enableRawInput(); err == nil {
+				defer restore()
+				interrupts := make(chan os.Signal, 1)
+				signal.Notify(interrupts, os.Interrupt)
+				go func() {
+					<-interrupts
+					restore()
+					fmt.Print(ansiShowCursor)
+					os.Exit(1)
+				}()
+				p.keys = make(chan byte)
+				go readKeys(p.keys)
+			}
+		}
+		fmt.Print(ansiHideCursor)
+		defer fmt.Print(ansiShowCursor)
+	}
+
+	next := func() bool {
+		return !tty || p.wait()
+	}
+
+	showFrame("Initial drawing", stacks, highlight{stack: -1}, tty)
+	for i, rearrangement := range rearrangements {
+		if !next() {
+			return
+		}
+		quantity, from, to, _ := getArguments(rearrangement)
+		title := fmt.Sprintf("Move %d/%d: %s", i+1, len(rearrangements), rearrangement)
+
+		showFrame(title, stacks, highlight{from - 1, quantity, ansiLifted}, tty)
+		if !next() {
+			return
+		}
+
+		if part == 1 {
+			moveItems(quantity, from, to, &stacks)
+		} else {
+			moveItemsWithoutReversing(quantity, from, to, &stacks)
+		}
+		showFrame(title, stacks, highlight{to - 1, quantity, ansiPlaced}, tty)
+	}
+
+	fmt.Println("Tops of stacks: ", getTops(&stacks))
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"parsing"
+	"testing"
+)
+
+const example = `    [D]    
+[N] [C]    
+[Z] [M] [P]
+ 1   2   3 
+
+move 1 from 2 to 1
+move 3 from 1 to 3
+move 2 from 2 to 1
+move 1 from 1 to 2
+`
+
+func addInputs(f *testing.F) {
+	f.Add([]byte(example), uint8(3))
+	if input, err := os.ReadFile("../../input/day5.txt"); err == nil {
+		f.Add(input, uint8(3))
+	}
+}
+
+// FuzzRearrangement checks that the input is either rejected with the line
+// at fault, or rearranged by both cranes without losing a crate, and that
+// the concurrent schedule ends like the sequential procedure
+func FuzzRearrangement(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data []byte, cranes uint8) {
+		stackInput, rearrangements, err := readInput(bytes.NewReader(data))
+		if err != nil {
+			var parseErr *parsing.Error
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("untyped error %v", err)
+			}
+			return
+		}
+
+		crates := 0
+		for _, stack := range buildStacks(stackInput) {
+			crates += stack.Size()
+		}
+		for part := 1; part <= 2; part++ {
+			stacks := buildStacks(stackInput)
+			rearrange(rearrangements, &stacks, part)
+			after := 0
+			for _, stack := range stacks {
+				after += stack.Size()
+			}
+			if after != crates {
+				t.Fatalf("part %d: %d crates before, %d after", part, crates, after)
+			}
+			if tops := getTops(&stacks); len(tops) > len(stacks) {
+				t.Fatalf("part %d: %d tops for %d stacks", part, len(tops), len(stacks))
+			}
+
+			moves, err := parseMoves(rearrangements)
+			if err != nil {
+				t.Fatalf("checked procedure does not parse: %v", err)
+			}
+			g := buildMoveGraph(moves, part)
+			s := scheduleMoves(g, int(cranes%4)+1)
+			if !sameStacks(stacks, simulateSchedule(stackInput, g, s, part)) {
+				t.Fatalf("part %d: the concurrent schedule ends differently", part)
+			}
+		}
+	})
+}
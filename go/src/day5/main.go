@@ -148,13 +148,13 @@ on top of each stack?
 package main
 
 import (
-	"bufio"
 	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"strconv"
-	"strings"
+	"parsing"
+	"time"
 )
 
 
@@ -229,12 +229,12 @@ func reverseStacks(stacks *[]Stack) {
 	}
 }
 
-func getArguments(s string) (int, int, int) {
-	parsed := strings.Split(s, " ")
-	from, _ := strconv.Atoi(parsed[3])
-	to, _ := strconv.Atoi(parsed[5])
-	quantity, _ := strconv.Atoi(parsed[1])
-	return quantity, from, to
+// getArguments reads a step of the procedure, checkMoves checks every
+// step when reading the input so the others can ignore the error
+func getArguments(s string) (int, int, int, error) {
+	var quantity, from, to int
+	err := parsing.Scan(s, "move {n} from {n} to {n}", &quantity, &from, &to)
+	return quantity, from, to, err
 }
 
 func addBoxesByLines(line string, stacks *[]Stack) {
@@ -278,7 +278,7 @@ func printStacks(stacks []Stack) {
 func buildStacks(stackInput []string) []Stack {
 	n := (len(stackInput[0]) + 1) / 4
 	stacks := make([]Stack, n)
-	for i := 0; i < n - 1; i++ {
+	for i := 0; i < len(stackInput) - 1; i++ {
 		addBoxesByLines(stackInput[i], &stacks)
 	}
 	reverseStacks(&stacks)
@@ -307,7 +307,7 @@ func moveItemsWithoutReversing(quantity int, from int, to int, stacks *[]Stack)
 
 func rearrange(rearrangements []string, stacks *[]Stack, part int) {
 	for _, rearrangement := range rearrangements {
-		quantity, from, to := getArguments(rearrangement)
+		quantity, from, to, _ := getArguments(rearrangement)
 		if part == 1 {
 			moveItems(quantity, from, to, stacks)
 		} else if part == 2 {
@@ -324,7 +324,11 @@ func rearrange(rearrangements []string, stacks *[]Stack, part int) {
 func getTops(stacks *[]Stack) string {
 	var tops string = ""
 	for i := 0; i < len(*stacks); i++ {
-		item, _ := (*stacks)[i].Peek()
+		item, err := (*stacks)[i].Peek()
+		if err != nil {
+			// an empty stack has nothing on top
+			continue
+		}
 		tops += item.(string)
 	}
 	return tops
@@ -337,36 +341,48 @@ func getSolution(rearrangements []string, stackInput []string, part int) string
 }
 
 func main() {
+	schedule := flag.Bool("schedule", false, "analyse a concurrent schedule of the moves")
+	cranes := flag.Int("cranes", 3, "number of cranes for the concurrent schedule")
+	animated := flag.Bool("animate", false, "play the rearrangement back in the terminal")
+	part := flag.Int("part", 1, "which crane to animate (1 or 2)")
+	speed := flag.Duration("speed", 200*time.Millisecond, "delay between animation frames")
+	flag.Parse()
+	if *cranes < 1 {
+		log.Fatal("Need at least one crane")
+	}
+
 	file, err := os.Open("../../input/day5.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	stackInput := []string{}
-	rearrangements := []string{}
-
-	// Reading till a blank lines is encountered,
-	// since the stack input and the rearrangement
-	// input are separated by a blank line
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			break
-		} else {
-			stackInput = append(stackInput, line)
-		}
+	stackInput, rearrangements, err := readInput(file)
+	if err != nil {
+		log.Fatal(err)
 	}
 
-	// Reading the rearrangement input
-	for scanner.Scan() {
-		line := scanner.Text()
-		rearrangements = append(rearrangements, line)
+	if *animated {
+		if *part != 1 && *part != 2 {
+			log.Fatal("Invalid part")
+		}
+		animate(rearrangements, stackInput, *part, *speed)
+		return
 	}
 
 	fmt.Println("Part One ------------------")
 	fmt.Println("Tops of stacks: ", getSolution(rearrangements, stackInput, 1))
 	fmt.Println("Part Two ------------------")
 	fmt.Println("Tops of stacks: ", getSolution(rearrangements, stackInput, 2))
+	if !*schedule {
+		return
+	}
+	fmt.Println("Concurrent schedule (part one) ----")
+	if err := analyseSchedule(rearrangements, stackInput, 1, *cranes); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Concurrent schedule (part two) ----")
+	if err := analyseSchedule(rearrangements, stackInput, 2, *cranes); err != nil {
+		log.Fatal(err)
+	}
 }
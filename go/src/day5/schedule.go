@@ -0,0 +1,325 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// craneMove is a single parsed step of the rearrangement procedure
+type craneMove struct {
+	quantity int
+	from     int
+	to       int
+}
+
+// moveGraph holds the dependencies between the moves of the procedure.
+//
+// Two moves conflict if they share a source or destination stack,
+// in which case the later one has to wait for the earlier one.
+// It is enough to link every move to the last earlier move touching
+// each of its stacks, the rest of the ordering follows transitively.
+type moveGraph struct {
+	moves      []craneMove
+	duration   []int
+	deps       [][]int
+	successors [][]int
+}
+
+// scheduledMove is a move placed on a crane at a given time
+type scheduledMove struct {
+	crane int
+	start int
+	end   int
+}
+
+// craneSchedule is the result of scheduling the moves on K cranes
+type craneSchedule struct {
+	cranes   int
+	slots    []scheduledMove
+	busy     []int
+	makespan int
+}
+
+// parseMoves reads the steps of the procedure, the errors tell which
+// step could not be read
+func parseMoves(rearrangements []string) ([]craneMove, error) {
+	moves := make([]craneMove, 0, len(rearrangements))
+	for i, rearrangement := range rearrangements {
+		quantity, from, to, err := getArguments(rearrangement)
+		if err != nil {
+			return nil, fmt.Errorf("move %d: %w", i+1, err)
+		}
+		moves = append(moves, craneMove{quantity, from, to})
+	}
+	return moves, nil
+}
+
+// moveDuration is the time a crane needs for a move.
+//
+// The CrateMover 9000 (part 1) lifts one crate at a time,
+// the CrateMover 9001 (part 2) lifts all of them at once.
+func moveDuration(move craneMove, part int) int {
+	if part == 1 {
+		return move.quantity
+	}
+	return 1
+}
+
+func buildMoveGraph(moves []craneMove, part int) moveGraph {
+	n := len(moves)
+	g := moveGraph{
+		moves:      moves,
+		duration:   make([]int, n),
+		deps:       make([][]int, n),
+		successors: make([][]int, n),
+	}
+
+	lastTouch := make(map[int]int)
+	for i, move := range moves {
+		g.duration[i] = moveDuration(move, part)
+		for _, stack := range []int{move.from, move.to} {
+			j, ok := lastTouch[stack]
+			if !ok || containsInt(g.deps[i], j) {
+				continue
+			}
+			g.deps[i] = append(g.deps[i], j)
+			g.successors[j] = append(g.successors[j], i)
+		}
+		lastTouch[move.from] = i
+		lastTouch[move.to] = i
+	}
+	return g
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// tailLengths returns, for every move, the length of the longest
+// dependency chain starting with that move (its own duration included)
+func tailLengths(g moveGraph) []int {
+	tails := make([]int, len(g.moves))
+	// successors always come later in the procedure,
+	// so a reverse scan visits them first
+	for i := len(g.moves) - 1; i >= 0; i-- {
+		longest := 0
+		for _, s := range g.successors[i] {
+			if tails[s] > longest {
+				longest = tails[s]
+			}
+		}
+		tails[i] = g.duration[i] + longest
+	}
+	return tails
+}
+
+// criticalPathLength is the length of the longest dependency chain,
+// no number of cranes can finish the procedure faster than that
+func criticalPathLength(g moveGraph) int {
+	length := 0
+	for _, tail := range tailLengths(g) {
+		if tail > length {
+			length = tail
+		}
+	}
+	return length
+}
+
+// scheduleLowerBound is the best makespan we could hope for with k cranes
+func scheduleLowerBound(g moveGraph, k int) int {
+	work := 0
+	for _, d := range g.duration {
+		work += d
+	}
+	bound := (work + k - 1) / k
+	if cp := criticalPathLength(g); cp > bound {
+		bound = cp
+	}
+	return bound
+}
+
+// scheduleMoves assigns the moves to k cranes.
+//
+// Finding the minimal makespan with precedence constraints is NP-hard,
+// so we use list scheduling with the longest remaining chain as priority
+// (which is optimal or very close to it for chain-heavy graphs like this one)
+// and report the lower bound alongside it to tell how far off we can be.
+func scheduleMoves(g moveGraph, k int) craneSchedule {
+	n := len(g.moves)
+	tails := tailLengths(g)
+	s := craneSchedule{
+		cranes: k,
+		slots:  make([]scheduledMove, n),
+		busy:   make([]int, k),
+	}
+
+	pending := make([]int, n)
+	ready := []int{}
+	for i := range g.moves {
+		pending[i] = len(g.deps[i])
+		if pending[i] == 0 {
+			ready = append(ready, i)
+		}
+	}
+
+	running := []int{}
+	free := make([]bool, k)
+	for c := range free {
+		free[c] = true
+	}
+
+	time := 0
+	for done := 0; done < n; {
+		sort.Slice(ready, func(a, b int) bool {
+			if tails[ready[a]] != tails[ready[b]] {
+				return tails[ready[a]] > tails[ready[b]]
+			}
+			return ready[a] < ready[b]
+		})
+
+		for c := 0; c < k && len(ready) > 0; c++ {
+			if !free[c] {
+				continue
+			}
+			move := ready[0]
+			ready = ready[1:]
+			free[c] = false
+			s.slots[move] = scheduledMove{c, time, time + g.duration[move]}
+			s.busy[c] += g.duration[move]
+			running = append(running, move)
+		}
+
+		// advance to the next time a crane finishes
+		next := -1
+		for _, move := range running {
+			if next == -1 || s.slots[move].end < next {
+				next = s.slots[move].end
+			}
+		}
+		time = next
+
+		stillRunning := running[:0]
+		for _, move := range running {
+			if s.slots[move].end != time {
+				stillRunning = append(stillRunning, move)
+				continue
+			}
+			done++
+			free[s.slots[move].crane] = true
+			for _, succ := range g.successors[move] {
+				pending[succ]--
+				if pending[succ] == 0 {
+					ready = append(ready, succ)
+				}
+			}
+		}
+		running = stillRunning
+	}
+
+	s.makespan = time
+	return s
+}
+
+// craneQueues returns the moves of every crane in the order it runs them
+func craneQueues(s craneSchedule) [][]int {
+	queues := make([][]int, s.cranes)
+	for move, slot := range s.slots {
+		queues[slot.crane] = append(queues[slot.crane], move)
+	}
+	for _, queue := range queues {
+		sort.Slice(queue, func(a, b int) bool {
+			return s.slots[queue[a]].start < s.slots[queue[b]].start
+		})
+	}
+	return queues
+}
+
+// simulateSchedule runs every crane in its own goroutine.
+//
+// A crane only starts a move once all of its dependencies are done,
+// which guarantees that no two cranes ever touch the same stack at once.
+func simulateSchedule(stackInput []string, g moveGraph, s craneSchedule, part int) []Stack {
+	stacks := buildStacks(stackInput)
+	done := make([]chan struct{}, len(g.moves))
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	var wg sync.WaitGroup
+	for _, queue := range craneQueues(s) {
+		wg.Add(1)
+		go func(queue []int) {
+			defer wg.Done()
+			for _, i := range queue {
+				for _, dep := range g.deps[i] {
+					<-done[dep]
+				}
+				move := g.moves[i]
+				if part == 1 {
+					moveItems(move.quantity, move.from, move.to, &stacks)
+				} else {
+					moveItemsWithoutReversing(move.quantity, move.from, move.to, &stacks)
+				}
+				close(done[i])
+			}
+		}(queue)
+	}
+	wg.Wait()
+
+	return stacks
+}
+
+func sameStacks(a []Stack, b []Stack) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Size() != b[i].Size() {
+			return false
+		}
+		for j, item := range a[i].Values() {
+			if b[i].Values()[j] != item {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func analyseSchedule(rearrangements []string, stackInput []string, part int, cranes int) error {
+	moves, err := parseMoves(rearrangements)
+	if err != nil {
+		return err
+	}
+	g := buildMoveGraph(moves, part)
+	s := scheduleMoves(g, cranes)
+	bound := scheduleLowerBound(g, cranes)
+
+	sequential := buildStacks(stackInput)
+	rearrange(rearrangements, &sequential, part)
+	concurrent := simulateSchedule(stackInput, g, s, part)
+
+	fmt.Println("Moves:", len(g.moves))
+	fmt.Println("Critical path length:", criticalPathLength(g))
+	fmt.Printf("Makespan with %d cranes: %d (lower bound %d)\n", cranes, s.makespan, bound)
+	if s.makespan == bound {
+		fmt.Println("Schedule is optimal")
+	} else {
+		fmt.Printf("Schedule may not be optimal: list scheduling found %d, no schedule can beat %d\n", s.makespan, bound)
+	}
+	for c, busy := range s.busy {
+		utilisation := 0.0
+		if s.makespan > 0 {
+			utilisation = 100 * float64(busy) / float64(s.makespan)
+		}
+		fmt.Printf("Crane %d: busy %d, utilisation %.1f%%\n", c+1, busy, utilisation)
+	}
+	fmt.Println("Matches sequential result:", sameStacks(sequential, concurrent))
+	return nil
+}
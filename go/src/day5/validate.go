@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"parsing"
+)
+
+// readInput reads the drawing of the stacks and the rearrangement
+// procedure, which are separated by a blank line, and checks them
+// so that the solutions can trust every line.
+func readInput(r io.Reader) ([]string, []string, error) {
+	records := parsing.NewRecordScanner(r)
+	var drawing, procedure parsing.Record
+	if records.Scan() {
+		drawing = records.Record()
+	}
+	if records.Scan() {
+		procedure = records.Record()
+	}
+	if err := records.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	sizes, err := checkDrawing(drawing)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := checkMoves(sizes, procedure); err != nil {
+		return nil, nil, err
+	}
+	return drawing.Lines, procedure.Lines, nil
+}
+
+// checkDrawing makes sure the drawing has the shape buildStacks expects:
+// crates like [A], marked with a printable ASCII character, in columns four
+// characters apart, above a line with the numbers of the stacks. It returns
+// how many crates each stack holds.
+func checkDrawing(drawing parsing.Record) ([]int, error) {
+	lines := drawing.Lines
+	if len(lines) == 0 {
+		return nil, &parsing.Error{Line: drawing.Line, Err: errors.New("no drawing of the stacks")}
+	}
+
+	n := (len(lines[0]) + 1) / 4
+	numbersLine := drawing.Line + len(lines) - 1
+	numbers, err := parsing.Ints(lines[len(lines)-1])
+	if err != nil {
+		return nil, parsing.AtLine(err, numbersLine)
+	}
+	if n == 0 || len(numbers) != n {
+		return nil, &parsing.Error{Line: numbersLine, Err: fmt.Errorf("expected the numbers of %d stacks", n)}
+	}
+	for i, number := range numbers {
+		if number != i+1 {
+			return nil, &parsing.Error{Line: numbersLine, Err: fmt.Errorf("stack %d is numbered %d", i+1, number)}
+		}
+	}
+
+	sizes := make([]int, n)
+	for l, line := range lines[:len(lines)-1] {
+		lineNumber := drawing.Line + l
+		if len(line) > 4*n-1 {
+			return nil, &parsing.Error{Line: lineNumber, Column: 4 * n, Err: fmt.Errorf("line is wider than %d stacks", n)}
+		}
+		for i := 0; i < len(line); i += 4 {
+			slot := line[i:min(i+3, len(line))]
+			switch {
+			case len(slot) < 3:
+				return nil, &parsing.Error{Line: lineNumber, Column: i + 1, Err: fmt.Errorf("crate %q is cut off", slot)}
+			case slot == "   ":
+			case slot[0] == '[' && slot[1] > ' ' && slot[1] <= '~' && slot[2] == ']':
+				sizes[i/4]++
+			default:
+				return nil, &parsing.Error{Line: lineNumber, Column: i + 1, Err: fmt.Errorf("expected a crate like [A], found %q", slot)}
+			}
+			if i+3 < len(line) && line[i+3] != ' ' {
+				return nil, &parsing.Error{Line: lineNumber, Column: i + 4, Err: fmt.Errorf("expected a space between stacks, found %q", line[i+3])}
+			}
+		}
+	}
+	return sizes, nil
+}
+
+// checkMoves makes sure every step of the procedure moves crates that are
+// there, between stacks that exist, by following the size of every stack
+func checkMoves(sizes []int, procedure parsing.Record) error {
+	sizes = append([]int{}, sizes...)
+	for i, rearrangement := range procedure.Lines {
+		lineNumber := procedure.Line + i
+		quantity, from, to, err := getArguments(rearrangement)
+		if err != nil {
+			return parsing.AtLine(err, lineNumber)
+		}
+
+		for _, stack := range []int{from, to} {
+			if stack < 1 || stack > len(sizes) {
+				return &parsing.Error{Line: lineNumber, Err: fmt.Errorf("there is no stack %d, only %d stacks", stack, len(sizes))}
+			}
+		}
+		if quantity < 0 || quantity > sizes[from-1] {
+			return &parsing.Error{Line: lineNumber, Err: fmt.Errorf("cannot move %d crates from stack %d holding %d", quantity, from, sizes[from-1])}
+		}
+		sizes[from-1] -= quantity
+		sizes[to-1] += quantity
+	}
+	return nil
+}
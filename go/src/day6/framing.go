@@ -0,0 +1,223 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+)
+
+const (
+	packetMarkerSize  = 4
+	messageMarkerSize = 14
+)
+
+// frame is the data between two successive markers of a datastream.
+//
+// Offsets count runes from the beginning of the stream like the marker
+// positions do: markerStart is where the marker opening the frame begins,
+// start and end delimit the payload. The last frame of a stream is not
+// closed by a marker, which is what final tells.
+type frame struct {
+	markerStart int
+	start       int
+	end         int
+	data        string
+	final       bool
+}
+
+// framer wraps a reader and splits it into frames.
+//
+// It first locks onto the stream by skipping everything up to the first
+// marker. After every marker the window is cleared, so the runes of the
+// next marker always belong to the current frame and never overlap the
+// previous marker.
+//
+// The frames come one by one from Next or Frames, while Read gives the
+// payloads one after the other without the markers. Both take from the
+// same stream, a frame is either returned or read.
+type framer struct {
+	reader      *bufio.Reader
+	markerSize  int
+	window      *markerWindow
+	pos         int
+	locked      bool
+	done        bool
+	markerStart int
+	// unread is what Read has not returned yet of the current payload
+	unread []byte
+}
+
+var _ io.Reader = (*framer)(nil)
+
+func newFramer(r io.Reader, markerSize int) *framer {
+	return &framer{
+		reader:     bufio.NewReader(r),
+		markerSize: markerSize,
+		window:     newMarkerWindow(markerSize),
+	}
+}
+
+func newPacketFramer(r io.Reader) *framer {
+	return newFramer(r, packetMarkerSize)
+}
+
+func newMessageFramer(r io.Reader) *framer {
+	return newFramer(r, messageMarkerSize)
+}
+
+func (f *framer) readRune() (rune, error) {
+	for {
+		c, _, err := f.reader.ReadRune()
+		if err != nil {
+			return 0, err
+		}
+		if c == '\n' || c == '\r' {
+			continue
+		}
+		f.pos++
+		return c, nil
+	}
+}
+
+// lock skips the stream up to the end of the first marker
+func (f *framer) lock() error {
+	for {
+		c, err := f.readRune()
+		if err == io.EOF {
+			return errNoMarker
+		}
+		if err != nil {
+			return err
+		}
+		if f.window.push(c) {
+			f.locked = true
+			f.markerStart = f.pos - f.markerSize
+			f.window.reset()
+			return nil
+		}
+	}
+}
+
+// Next returns the next frame of the stream, or io.EOF once it is exhausted.
+// A stream without any marker fails with errNoMarker.
+func (f *framer) Next() (frame, error) {
+	if f.done {
+		return frame{}, io.EOF
+	}
+	if !f.locked {
+		if err := f.lock(); err != nil {
+			f.done = true
+			return frame{}, err
+		}
+	}
+
+	start := f.pos
+	payload := []rune{}
+	for {
+		c, err := f.readRune()
+		if err == io.EOF {
+			f.done = true
+			if len(payload) == 0 {
+				return frame{}, io.EOF
+			}
+			return frame{f.markerStart, start, f.pos, string(payload), true}, nil
+		}
+		if err != nil {
+			f.done = true
+			return frame{}, err
+		}
+
+		payload = append(payload, c)
+		if f.window.push(c) {
+			end := f.pos - f.markerSize
+			fr := frame{f.markerStart, start, end, string(payload[:len(payload)-f.markerSize]), false}
+			f.markerStart = end
+			f.window.reset()
+			return fr, nil
+		}
+	}
+}
+
+// Read reads the payloads of the frames as a single stream, as UTF-8.
+// It returns io.EOF after the last payload, and errNoMarker for a stream
+// without any marker.
+func (f *framer) Read(p []byte) (int, error) {
+	for len(f.unread) == 0 {
+		fr, err := f.Next()
+		if err != nil {
+			return 0, err
+		}
+		f.unread = []byte(fr.data)
+	}
+	n := copy(p, f.unread)
+	f.unread = f.unread[n:]
+	return n, nil
+}
+
+// Frames sends every frame of the stream on the returned channel.
+// The error channel receives at most one error once the frames are done.
+// Closing done stops the framer early, both channels are then closed
+// without an error.
+func (f *framer) Frames(done <-chan struct{}) (<-chan frame, <-chan error) {
+	frames := make(chan frame)
+	errs := make(chan error, 1)
+	go func() {
+		defer close(frames)
+		defer close(errs)
+		for {
+			fr, err := f.Next()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case frames <- fr:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return frames, errs
+}
+
+func printFrames(name string, f *framer) {
+	done := make(chan struct{})
+	defer close(done)
+	frames, errs := f.Frames(done)
+	count, longest := 0, frame{}
+	for fr := range frames {
+		if count < 5 {
+			fmt.Printf("%s %d: marker at %d, payload %d-%d %q\n", name, count+1, fr.markerStart, fr.start, fr.end, fr.data)
+		}
+		if fr.end-fr.start > longest.end-longest.start {
+			longest = fr
+		}
+		count++
+	}
+	if err := <-errs; err != nil {
+		fmt.Printf("%ss: %v\n", name, err)
+		return
+	}
+	fmt.Printf("%ss: %d, longest payload at %d-%d (%d runes)\n", name, count, longest.start, longest.end, longest.end-longest.start)
+}
+
+// frameFile splits a recorded datastream into packets and messages
+func frameFile(filePath string) error {
+	for _, kind := range []string{"Packet", "Message"} {
+		file, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		if kind == "Packet" {
+			printFrames(kind, newPacketFramer(file))
+		} else {
+			printFrames(kind, newMessageFramer(file))
+		}
+		file.Close()
+	}
+	return nil
+}
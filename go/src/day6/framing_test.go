@@ -0,0 +1,108 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// TestFramesDone checks that the framer stops when the reader of the
+// frames gives up after the first one
+func TestFramesDone(t *testing.T) {
+	stream := strings.Repeat("abcd", 100)
+	done := make(chan struct{})
+	frames, errs := newPacketFramer(strings.NewReader(stream)).Frames(done)
+
+	first, ok := <-frames
+	if !ok {
+		t.Fatal("no frames")
+	}
+	if first.markerStart != 0 || first.start != 4 || first.end != 4 {
+		t.Fatalf("first frame spans %d-%d after a marker at %d", first.start, first.end, first.markerStart)
+	}
+
+	close(done)
+	// the framer may have sent one more frame before seeing done
+	for range frames {
+	}
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readFrames returns every frame of the stream
+func readFrames(t *testing.T, stream string, markerSize int) []frame {
+	t.Helper()
+	f := newFramer(strings.NewReader(stream), markerSize)
+	frames := []frame{}
+	for {
+		fr, err := f.Next()
+		if err == io.EOF {
+			return frames
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		frames = append(frames, fr)
+	}
+}
+
+func TestFrameBoundaries(t *testing.T) {
+	frames := readFrames(t, "aaaabcdaabcde", 4)
+	want := []frame{
+		{markerStart: 3, start: 7, end: 8, data: "a"},
+		{markerStart: 8, start: 12, end: 13, data: "e", final: true},
+	}
+	if !slices.Equal(frames, want) {
+		t.Fatalf("frames %v, expected %v", frames, want)
+	}
+}
+
+// TestExampleFrames checks that the first frame of every example starts
+// after the puzzle's marker, that each frame ends with a marker and that
+// Read gives back the payloads
+func TestExampleFrames(t *testing.T) {
+	firstMarkers := map[int][]int{
+		packetMarkerSize:  {7, 5, 6, 10, 11},
+		messageMarkerSize: {19, 23, 23, 29, 26},
+	}
+	for markerSize, answers := range firstMarkers {
+		for i, example := range examples {
+			frames := readFrames(t, example, markerSize)
+			if len(frames) == 0 || frames[0].start != answers[i] || frames[0].markerStart != answers[i]-markerSize {
+				t.Fatalf("%s: frames %v, expected the first after character %d", example, frames, answers[i])
+			}
+
+			payloads := ""
+			for j, fr := range frames {
+				if fr.start != fr.markerStart+markerSize || example[fr.start:fr.end] != fr.data {
+					t.Fatalf("%s: frame %d is %v", example, j+1, fr)
+				}
+				if fr.final != (j == len(frames)-1) {
+					t.Fatalf("%s: frame %d of %d is final %v", example, j+1, len(frames), fr.final)
+				}
+				if !fr.final && frames[j+1].markerStart != fr.end {
+					t.Fatalf("%s: frame %d ends at %d, the next marker is at %d", example, j+1, fr.end, frames[j+1].markerStart)
+				}
+				if !fr.final && getMarkerIndex(example[fr.end:fr.end+markerSize], markerSize) != markerSize {
+					t.Fatalf("%s: frame %d is not followed by a marker", example, j+1)
+				}
+				payloads += fr.data
+			}
+
+			read, err := io.ReadAll(newFramer(strings.NewReader(example), markerSize))
+			if err != nil || string(read) != payloads {
+				t.Fatalf("%s: read %q (%v), expected %q", example, read, err, payloads)
+			}
+		}
+	}
+}
+
+func TestReadNoMarker(t *testing.T) {
+	_, err := io.ReadAll(newPacketFramer(strings.NewReader("aaaaaaa")))
+	if !errors.Is(err, errNoMarker) {
+		t.Fatalf("reading a stream without markers: %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// the examples of both parts
+var examples = []string{
+	"mjqjpqmgbljsphdztnvjfqwrcgsmlb",
+	"bvwbjplbgvbhsrlpgdmjqwftvncz",
+	"nppdvjthqldpwncqszvftbrmjlhg",
+	"nznrnfrfntjfmvfwmzdfjlvtqnbhcprsg",
+	"zcfzfwzzqfrljwzlrfnpqdbhtmscgvjw",
+}
+
+func addInputs(f *testing.F) {
+	for _, example := range examples {
+		f.Add(example, uint8(4))
+		f.Add(example, uint8(14))
+	}
+	if input, err := os.ReadFile("../../input/day6.txt"); err == nil {
+		f.Add(string(input), uint8(4))
+		f.Add(string(input), uint8(14))
+	}
+}
+
+// signal is the stream as the scanners see it, runes without line breaks
+func signal(data string) []rune {
+	runes := []rune{}
+	for _, c := range data {
+		if c != '\n' && c != '\r' {
+			runes = append(runes, c)
+		}
+	}
+	return runes
+}
+
+// FuzzMarkers compares the streaming scanners with the original solution
+// on the signal, and with the noisy candidates without any noise
+func FuzzMarkers(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data string, size uint8) {
+		markerSize := int(size%16) + 1
+		runes := signal(data)
+		// the original solution works on bytes, which only are
+		// the runes when the stream is ASCII
+		ascii := true
+		for _, c := range runes {
+			ascii = ascii && c < utf8.RuneSelf
+		}
+
+		index, err := findMarker(strings.NewReader(data), markerSize)
+		markers, allErr := findAllMarkers(strings.NewReader(data), markerSize)
+		if err != nil {
+			if !errors.Is(err, errNoMarker) || !errors.Is(allErr, errNoMarker) {
+				t.Fatalf("unexpected errors %v and %v", err, allErr)
+			}
+			if ascii && getMarkerIndex(string(runes), markerSize) != -1 {
+				t.Fatalf("missed the marker of %q", string(runes))
+			}
+			return
+		}
+		if allErr != nil || len(markers) == 0 || markers[0] != index {
+			t.Fatalf("first marker %d but all markers %v, %v", index, markers, allErr)
+		}
+		if ascii {
+			if want := getMarkerIndex(string(runes), markerSize); index != want {
+				t.Fatalf("marker at %d, expected %d", index, want)
+			}
+		}
+
+		candidates := noisyCandidates(runes, markerSize, 0)
+		if len(candidates) != len(markers) {
+			t.Fatalf("%d markers but %d candidates", len(markers), len(candidates))
+		}
+		for i, c := range candidates {
+			if c.end != markers[i] {
+				t.Fatalf("candidate %d ends at %d, marker at %d", i, c.end, markers[i])
+			}
+		}
+	})
+}
+
+// FuzzFrames checks that the frames and the markers between them
+// give back the signal after the first marker
+func FuzzFrames(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data string, size uint8) {
+		markerSize := int(size%16) + 1
+		runes := signal(data)
+		framer := newFramer(strings.NewReader(data), markerSize)
+
+		var first frame
+		end := 0
+		for i := 0; ; i++ {
+			fr, err := framer.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				if !errors.Is(err, errNoMarker) || i > 0 {
+					t.Fatalf("frame %d: %v", i+1, err)
+				}
+				return
+			}
+			if i == 0 {
+				first = fr
+			}
+			if fr.start < fr.markerStart || fr.end < fr.start || fr.end > len(runes) {
+				t.Fatalf("frame %d spans %d-%d of %d runes", i+1, fr.start, fr.end, len(runes))
+			}
+			if string(runes[fr.start:fr.end]) != fr.data {
+				t.Fatalf("frame %d holds %q, expected %q", i+1, fr.data, string(runes[fr.start:fr.end]))
+			}
+			if fr.final && fr.end != len(runes) {
+				t.Fatalf("the final frame ends at %d of %d runes", fr.end, len(runes))
+			}
+			end = fr.end
+		}
+		if end > 0 && first.start != first.markerStart+markerSize {
+			t.Fatalf("the first frame starts at %d after a marker at %d", first.start, first.markerStart)
+		}
+	})
+}
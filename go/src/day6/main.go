@@ -81,10 +81,12 @@ marker is detected?
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"parsing"
+	"strings"
 )
 
 func hasUnique(s string) bool {
@@ -99,7 +101,7 @@ func hasUnique(s string) bool {
 }
 
 func getMarkerIndex(s string, markerSize int) int {
-	for i := 0; i < len(s)-3; i++ {
+	for i := 0; i <= len(s)-markerSize; i++ {
 		if hasUnique(s[i : i+markerSize]) {
 			return i + markerSize
 		}
@@ -107,37 +109,131 @@ func getMarkerIndex(s string, markerSize int) int {
 	return -1
 }
 
-func getSolution(streams []string, part int) {
-	fmt.Println("Minimum number of characters to process:")
+func markerSize(part int) int {
 	if part == 1 {
-		for i, s := range streams {
-			fmt.Printf("Stream %d: %d\n", i+1, getMarkerIndex(s, 4))
+		return 4
+	}
+	return 14
+}
+
+func getSolution(streams []string, part int, all bool) {
+	if part != 1 && part != 2 {
+		fmt.Println("Invalid part number")
+		return
+	}
+
+	fmt.Println("Minimum number of characters to process:")
+	for i, s := range streams {
+		if all {
+			markers, err := findAllMarkers(strings.NewReader(s), markerSize(part))
+			if err != nil {
+				fmt.Printf("Stream %d: %v\n", i+1, err)
+				continue
+			}
+			fmt.Printf("Stream %d: %d markers at %v\n", i+1, len(markers), markers)
+			continue
 		}
-	} else if part == 2 {
-		for i, s := range streams {
-			fmt.Printf("Stream %d: %d\n", i+1, getMarkerIndex(s, 14))
+
+		index, err := findMarker(strings.NewReader(s), markerSize(part))
+		if err != nil {
+			fmt.Printf("Stream %d: %v\n", i+1, err)
+			continue
 		}
-	} else {
-		fmt.Println("Invalid part number")
+		fmt.Printf("Stream %d: %d\n", i+1, index)
 	}
 }
 
+// scanFile treats a whole file as one datastream and never holds
+// more than the marker window in memory, so it works on huge recordings
+func scanFile(filePath string, part int, all bool) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	if !all {
+		index, err := findMarker(file, markerSize(part))
+		if err != nil {
+			fmt.Println("Marker:", err)
+			return
+		}
+		fmt.Println("Marker:", index)
+		return
+	}
+
+	count, first, last := 0, 0, 0
+	err = eachMarker(file, markerSize(part), func(i int) bool {
+		if count == 0 {
+			first = i
+		}
+		count++
+		last = i
+		return true
+	})
+	if err != nil {
+		fmt.Println("Markers:", err)
+		return
+	}
+	fmt.Printf("Markers: %d (first at %d, last at %d)\n", count, first, last)
+}
+
 func main() {
+	all := flag.Bool("all", false, "report every marker position instead of only the first")
+	stream := flag.String("stream", "", "scan a whole file as one datastream without loading it")
+	frames := flag.String("frames", "", "split a recorded datastream into packets and messages")
+	tolerance := flag.Int("tolerance", -1, "list candidate markers with at most this many duplicates")
+	noise := flag.Float64("noise", 0, "simulate a noisy channel with this error rate per character")
+	trials := flag.Int("trials", 1000, "number of noisy copies for the simulation")
+	seed := flag.Int64("seed", 1, "seed of the noise simulation")
+	flag.Parse()
+
+	if *frames != "" {
+		if err := frameFile(*frames); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if *stream != "" {
+		fmt.Println("--------------- Part One ----------------")
+		scanFile(*stream, 1, *all)
+		fmt.Println("--------------- Part Two ----------------")
+		scanFile(*stream, 2, *all)
+		return
+	}
+
 	file, err := os.Open("../../input/day6.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer file.Close()
 
-	scanner := bufio.NewScanner(file)
-	streams := []string{}
-	for scanner.Scan() {
-		line := scanner.Text()
-		streams = append(streams, line)
+	streams, err := parsing.ReadLines(file)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *tolerance >= 0 || *noise > 0 {
+		if *tolerance < 0 {
+			*tolerance = 0
+		}
+		if *trials < 1 {
+			log.Fatal("Need at least one trial")
+		}
+		for part := 1; part <= 2; part++ {
+			fmt.Printf("--------------- Part %d, tolerance %d ----------------\n", part, *tolerance)
+			if *noise > 0 {
+				printNoiseReport(streams, markerSize(part), *tolerance, *noise, *trials, *seed)
+			} else {
+				printCandidates(streams, markerSize(part), *tolerance)
+			}
+		}
+		return
 	}
 
 	fmt.Println("--------------- Part One ----------------")
-	getSolution(streams, 1)
+	getSolution(streams, 1, *all)
 	fmt.Println("--------------- Part Two ----------------")
-	getSolution(streams, 2)
+	getSolution(streams, 2, *all)
 }
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// candidate is a window that is close enough to a marker.
+//
+// end is the number of runes processed, like getMarkerIndex reports,
+// and confidence is the share of distinct runes in the window.
+type candidate struct {
+	end        int
+	duplicates int
+	confidence float64
+}
+
+// noiseReport sums up how well markers survive a corrupted channel
+type noiseReport struct {
+	trials int
+	hits   int
+	early  int
+	late   int
+	missed int
+}
+
+// duplicates is the number of runes in a full window that repeat an
+// earlier one, a real marker has none
+func (w *markerWindow) duplicates() int {
+	return w.size - w.distinct
+}
+
+// noisyCandidates returns every window of markerSize runes with at most
+// maxDuplicates repeated runes, so at least markerSize-maxDuplicates of
+// them are distinct. With maxDuplicates set to 0 these are exactly the
+// markers of the stream.
+func noisyCandidates(s []rune, markerSize int, maxDuplicates int) []candidate {
+	candidates := []candidate{}
+	window := newMarkerWindow(markerSize)
+	for _, c := range s {
+		window.push(c)
+		if window.seen < markerSize || window.duplicates() > maxDuplicates {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			end:        window.seen,
+			duplicates: window.duplicates(),
+			confidence: float64(window.distinct) / float64(markerSize),
+		})
+	}
+	return candidates
+}
+
+// rankCandidates orders the candidates from the most to the least likely
+// marker, earlier windows win ties since the device locks on the first one
+func rankCandidates(candidates []candidate) {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].duplicates != candidates[j].duplicates {
+			return candidates[i].duplicates < candidates[j].duplicates
+		}
+		return candidates[i].end < candidates[j].end
+	})
+}
+
+// bestCandidate returns the most likely marker of a possibly corrupted stream
+func bestCandidate(s []rune, markerSize int, maxDuplicates int) (candidate, error) {
+	candidates := noisyCandidates(s, markerSize, maxDuplicates)
+	if len(candidates) == 0 {
+		return candidate{}, errNoMarker
+	}
+	rankCandidates(candidates)
+	return candidates[0], nil
+}
+
+func alphabet(s []rune) []rune {
+	seen := make(map[rune]bool)
+	symbols := []rune{}
+	for _, c := range s {
+		if !seen[c] {
+			seen[c] = true
+			symbols = append(symbols, c)
+		}
+	}
+	return symbols
+}
+
+// corrupt sends a stream through a noisy channel.
+//
+// Every rune is substituted, deleted or preceded by an inserted rune with
+// the given rate, split evenly between the three kinds of error.
+// origin maps every rune of the result to its index in the original
+// stream, inserted runes are -1.
+func corrupt(s []rune, rate float64, rng *rand.Rand) ([]rune, []int) {
+	symbols := alphabet(s)
+	noisy := make([]rune, 0, len(s))
+	origin := make([]int, 0, len(s))
+
+	for i, c := range s {
+		if rng.Float64() >= rate {
+			noisy = append(noisy, c)
+			origin = append(origin, i)
+			continue
+		}
+
+		switch rng.Intn(3) {
+		case 0:
+			noisy = append(noisy, symbols[rng.Intn(len(symbols))])
+			origin = append(origin, i)
+		case 1:
+			// deleted
+		case 2:
+			noisy = append(noisy, symbols[rng.Intn(len(symbols))], c)
+			origin = append(origin, -1, i)
+		}
+	}
+	return noisy, origin
+}
+
+// originalEnd maps the end of a window in a corrupted stream back
+// to the number of runes of the original stream it corresponds to
+func originalEnd(end int, origin []int) int {
+	for i := end - 1; i >= 0; i-- {
+		if origin[i] >= 0 {
+			return origin[i] + 1
+		}
+	}
+	return 0
+}
+
+// simulateNoise corrupts a stream over and over and checks whether the
+// best candidate still points at the marker of the clean stream
+func simulateNoise(s string, markerSize int, maxDuplicates int, rate float64, trials int, seed int64) (noiseReport, error) {
+	clean := []rune(s)
+	truth, err := bestCandidate(clean, markerSize, 0)
+	if err != nil {
+		return noiseReport{}, err
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+	report := noiseReport{trials: trials}
+	for t := 0; t < trials; t++ {
+		noisy, origin := corrupt(clean, rate, rng)
+		found, err := bestCandidate(noisy, markerSize, maxDuplicates)
+		if err != nil {
+			report.missed++
+			continue
+		}
+
+		end := originalEnd(found.end, origin)
+		if end == truth.end {
+			report.hits++
+		} else if end < truth.end {
+			report.early++
+		} else {
+			report.late++
+		}
+	}
+	return report, nil
+}
+
+func printCandidates(streams []string, markerSize int, maxDuplicates int) {
+	for i, s := range streams {
+		candidates := noisyCandidates([]rune(s), markerSize, maxDuplicates)
+		rankCandidates(candidates)
+		fmt.Printf("Stream %d: %d candidates\n", i+1, len(candidates))
+		for j := 0; j < len(candidates) && j < 5; j++ {
+			c := candidates[j]
+			fmt.Printf("  at %d, %d duplicates, confidence %.2f\n", c.end, c.duplicates, c.confidence)
+		}
+	}
+}
+
+func printNoiseReport(streams []string, markerSize int, maxDuplicates int, rate float64, trials int, seed int64) {
+	for i, s := range streams {
+		report, err := simulateNoise(s, markerSize, maxDuplicates, rate, trials, seed)
+		if err != nil {
+			fmt.Printf("Stream %d: %v\n", i+1, err)
+			continue
+		}
+		accuracy := 100 * float64(report.hits) / float64(report.trials)
+		fmt.Printf("Stream %d: accuracy %.1f%% (%d early, %d late, %d missed of %d)\n",
+			i+1, accuracy, report.early, report.late, report.missed, report.trials)
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+)
+
+// errNoMarker is returned when a stream ends without a marker
+var errNoMarker = errors.New("no marker found")
+
+// markerWindow is a sliding window over the last size runes of a stream.
+//
+// Instead of rebuilding a set for every window it keeps a count per symbol
+// and the number of distinct symbols in the window, so moving the window
+// by one rune is O(1) and the memory only grows with the alphabet.
+type markerWindow struct {
+	size     int
+	ring     []rune
+	counts   map[rune]int
+	distinct int
+	seen     int
+}
+
+func newMarkerWindow(size int) *markerWindow {
+	return &markerWindow{
+		size:   size,
+		ring:   make([]rune, size),
+		counts: make(map[rune]int),
+	}
+}
+
+// push adds a rune to the window and reports whether
+// the window is now full of distinct runes
+func (w *markerWindow) push(r rune) bool {
+	slot := w.seen % w.size
+	if w.seen >= w.size {
+		old := w.ring[slot]
+		w.counts[old]--
+		if w.counts[old] == 0 {
+			delete(w.counts, old)
+			w.distinct--
+		}
+	}
+
+	w.ring[slot] = r
+	w.counts[r]++
+	if w.counts[r] == 1 {
+		w.distinct++
+	}
+	w.seen++
+
+	return w.distinct == w.size
+}
+
+// reset empties the window so that the next marker starts from scratch
+func (w *markerWindow) reset() {
+	w.counts = make(map[rune]int)
+	w.distinct = 0
+	w.seen = 0
+}
+
+// eachMarker streams r rune by rune and calls found with the number of
+// runes processed every time the last markerSize runes are all different.
+// Returning false from found stops the scan.
+//
+// Line breaks are not part of the signal and are skipped, invalid UTF-8
+// bytes are read as utf8.RuneError, so they all count as the same symbol.
+// errNoMarker is returned if found was never called.
+func eachMarker(r io.Reader, markerSize int, found func(int) bool) error {
+	if markerSize < 1 {
+		return errors.New("marker size must be positive")
+	}
+
+	reader := bufio.NewReader(r)
+	window := newMarkerWindow(markerSize)
+	markers := 0
+	for {
+		c, _, err := reader.ReadRune()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if c == '\n' || c == '\r' {
+			continue
+		}
+
+		if window.push(c) {
+			markers++
+			if !found(window.seen) {
+				return nil
+			}
+		}
+	}
+
+	if markers == 0 {
+		return errNoMarker
+	}
+	return nil
+}
+
+// findMarker returns the number of runes to process before the first marker
+func findMarker(r io.Reader, markerSize int) (int, error) {
+	index := 0
+	err := eachMarker(r, markerSize, func(i int) bool {
+		index = i
+		return false
+	})
+	return index, err
+}
+
+// findAllMarkers returns the end position of every marker in the stream
+func findAllMarkers(r io.Reader, markerSize int) ([]int, error) {
+	markers := []int{}
+	err := eachMarker(r, markerSize, func(i int) bool {
+		markers = append(markers, i)
+		return true
+	})
+	return markers, err
+}
@@ -0,0 +1,48 @@
+package geometry
+
+// Box is the rectangle from Min to Max, both corners included
+type Box struct {
+	Min Point
+	Max Point
+}
+
+// BoundingBox returns the smallest box holding all the points
+func BoundingBox(points []Point) (Box, bool) {
+	if len(points) == 0 {
+		return Box{}, false
+	}
+	box := Box{points[0], points[0]}
+	for _, p := range points[1:] {
+		box = box.Extend(p)
+	}
+	return box, true
+}
+
+// Extend returns the smallest box holding the box and the point
+func (b Box) Extend(p Point) Box {
+	if p.X < b.Min.X {
+		b.Min.X = p.X
+	}
+	if p.Y < b.Min.Y {
+		b.Min.Y = p.Y
+	}
+	if p.X > b.Max.X {
+		b.Max.X = p.X
+	}
+	if p.Y > b.Max.Y {
+		b.Max.Y = p.Y
+	}
+	return b
+}
+
+func (b Box) Contains(p Point) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X && p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+func (b Box) Width() int {
+	return b.Max.X - b.Min.X + 1
+}
+
+func (b Box) Height() int {
+	return b.Max.Y - b.Min.Y + 1
+}
@@ -0,0 +1,64 @@
+package geometry
+
+import "testing"
+
+func TestBoxContains(t *testing.T) {
+	box := Box{Point{-2, 1}, Point{3, 4}}
+	tests := []struct {
+		p    Point
+		want bool
+	}{
+		{Point{-2, 1}, true},
+		{Point{3, 4}, true},
+		{Point{0, 2}, true},
+		{Point{-3, 2}, false},
+		{Point{4, 2}, false},
+		{Point{0, 0}, false},
+		{Point{0, 5}, false},
+	}
+	for _, tt := range tests {
+		if got := box.Contains(tt.p); got != tt.want {
+			t.Errorf("%v contains %v: %v, expected %v", box, tt.p, got, tt.want)
+		}
+	}
+	if box.Width() != 6 || box.Height() != 4 {
+		t.Errorf("%v is %dx%d, expected 6x4", box, box.Width(), box.Height())
+	}
+}
+
+func TestBoxUnion(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Point
+		want   Box
+		found  bool
+	}{
+		{"none", nil, Box{}, false},
+		{"single point", []Point{{5, -5}}, Box{Point{5, -5}, Point{5, -5}}, true},
+		{"corners", []Point{{3, 1}, {-1, 4}}, Box{Point{-1, 1}, Point{3, 4}}, true},
+		{"inside", []Point{{0, 0}, {4, 4}, {2, 3}}, Box{Point{0, 0}, Point{4, 4}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box, found := BoundingBox(tt.points)
+			if box != tt.want || found != tt.found {
+				t.Fatalf("bounding box %v (%v), expected %v (%v)", box, found, tt.want, tt.found)
+			}
+			for _, p := range tt.points {
+				if !box.Contains(p) {
+					t.Fatalf("%v is not in %v", p, box)
+				}
+			}
+		})
+	}
+
+	// extending by the corners of another box is the union of both
+	a, b := Box{Point{0, 0}, Point{2, 2}}, Box{Point{5, -3}, Point{6, 1}}
+	union := a.Extend(b.Min).Extend(b.Max)
+	if want := (Box{Point{0, -3}, Point{6, 2}}); union != want {
+		t.Fatalf("union of %v and %v is %v, expected %v", a, b, union, want)
+	}
+	if a.Extend(Point{1, 1}) != a {
+		t.Fatalf("extending %v by a point inside changes it", a)
+	}
+}
@@ -0,0 +1,125 @@
+package geometry
+
+// Direction4 is one of the four directions sharing a side with a cell.
+// The values go clockwise, so that turning is adding.
+type Direction4 int
+
+const (
+	North Direction4 = iota
+	East
+	South
+	West
+)
+
+var offsets4 = [4]Point{
+	North: {0, -1},
+	East:  {1, 0},
+	South: {0, 1},
+	West:  {-1, 0},
+}
+
+var names4 = [4]string{"north", "east", "south", "west"}
+
+// Offset is the step to take to move one cell this way
+func (d Direction4) Offset() Point {
+	return offsets4[d]
+}
+
+// Rotate turns the direction by quarter turns, clockwise when positive
+func (d Direction4) Rotate(quarterTurns int) Direction4 {
+	return Direction4(mod(int(d)+quarterTurns, 4))
+}
+
+func (d Direction4) Right() Direction4 {
+	return d.Rotate(1)
+}
+
+func (d Direction4) Left() Direction4 {
+	return d.Rotate(-1)
+}
+
+func (d Direction4) Opposite() Direction4 {
+	return d.Rotate(2)
+}
+
+// Direction8 is the same direction out of all eight
+func (d Direction4) Direction8() Direction8 {
+	return Direction8(2 * d)
+}
+
+func (d Direction4) String() string {
+	return names4[d]
+}
+
+// Direction8 is one of the eight directions touching a cell,
+// clockwise in steps of 45 degrees
+type Direction8 int
+
+const (
+	N Direction8 = iota
+	NE
+	E
+	SE
+	S
+	SW
+	W
+	NW
+)
+
+var offsets8 = [8]Point{
+	N:  {0, -1},
+	NE: {1, -1},
+	E:  {1, 0},
+	SE: {1, 1},
+	S:  {0, 1},
+	SW: {-1, 1},
+	W:  {-1, 0},
+	NW: {-1, -1},
+}
+
+var names8 = [8]string{"north", "northeast", "east", "southeast", "south", "southwest", "west", "northwest"}
+
+// Offset is the step to take to move one cell this way, a diagonal
+// step moves one cell horizontally and one vertically at once
+func (d Direction8) Offset() Point {
+	return offsets8[d]
+}
+
+// Rotate turns the direction by steps of 45 degrees, clockwise when positive
+func (d Direction8) Rotate(eighths int) Direction8 {
+	return Direction8(mod(int(d)+eighths, 8))
+}
+
+// Turn turns the direction by the degrees, clockwise when positive.
+// The turn is first brought into 0..359 degrees, then only whole steps
+// of 45 degrees count, so -100 degrees turns like 260, by 5 steps.
+func (d Direction8) Turn(degrees int) Direction8 {
+	return d.Rotate(mod(degrees, 360) / 45)
+}
+
+func (d Direction8) Right() Direction8 {
+	return d.Rotate(2)
+}
+
+func (d Direction8) Left() Direction8 {
+	return d.Rotate(-2)
+}
+
+func (d Direction8) Opposite() Direction8 {
+	return d.Rotate(4)
+}
+
+// Diagonal reports whether the direction is between two of the four
+func (d Direction8) Diagonal() bool {
+	return d%2 == 1
+}
+
+func (d Direction8) String() string {
+	return names8[d]
+}
+
+// mod is the remainder that is never negative, so turning
+// left from north comes back around to the west
+func mod(a int, n int) int {
+	return ((a % n) + n) % n
+}
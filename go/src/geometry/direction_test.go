@@ -0,0 +1,83 @@
+package geometry
+
+import "testing"
+
+func TestDirection4Rotate(t *testing.T) {
+	tests := []struct {
+		d            Direction4
+		quarterTurns int
+		want         Direction4
+	}{
+		{North, 1, East},
+		{North, -1, West},
+		{West, 1, North},
+		{South, 2, North},
+		{East, 4, East},
+		{East, -5, North},
+		{North, 7, West},
+	}
+	for _, tt := range tests {
+		if got := tt.d.Rotate(tt.quarterTurns); got != tt.want {
+			t.Errorf("%v rotated %d quarter turns is %v, expected %v", tt.d, tt.quarterTurns, got, tt.want)
+		}
+	}
+	for d := North; d < Direction4(4); d++ {
+		if d.Right().Left() != d || d.Opposite().Opposite() != d || d.Opposite() != d.Right().Right() {
+			t.Errorf("%v turns right %v, left %v and around %v", d, d.Right(), d.Left(), d.Opposite())
+		}
+		if d.Direction8().Offset() != d.Offset() {
+			t.Errorf("%v is %v out of eight, which moves %v instead of %v", d, d.Direction8(), d.Direction8().Offset(), d.Offset())
+		}
+	}
+}
+
+func TestDirection8Turn(t *testing.T) {
+	tests := []struct {
+		d       Direction8
+		degrees int
+		want    Direction8
+	}{
+		{N, 0, N},
+		{N, 45, NE},
+		{N, 90, E},
+		{N, -45, NW},
+		{N, -90, W},
+		{E, 180, W},
+		{NW, 90, NE},
+		{N, 360, N},
+		{N, 405, NE},
+		// only whole steps of 45 degrees count
+		{N, 44, N},
+		{N, 100, E},
+		// negative turns are brought into 0..359 degrees first
+		{N, -100, SW},
+		{N, -44, NW},
+		{S, -720, S},
+	}
+	for _, tt := range tests {
+		if got := tt.d.Turn(tt.degrees); got != tt.want {
+			t.Errorf("%v turned %d degrees is %v, expected %v", tt.d, tt.degrees, got, tt.want)
+		}
+	}
+}
+
+func TestDirection8Rotate(t *testing.T) {
+	for d := N; d < Direction8(8); d++ {
+		if d.Rotate(8) != d || d.Rotate(-8) != d || d.Rotate(3).Rotate(-3) != d {
+			t.Errorf("%v does not come back after a full rotation", d)
+		}
+		if d.Right() != d.Rotate(2) || d.Left() != d.Rotate(6) || d.Opposite() != d.Rotate(4) {
+			t.Errorf("%v turns right %v, left %v and around %v", d, d.Right(), d.Left(), d.Opposite())
+		}
+		offset := d.Offset()
+		if opposite := d.Opposite().Offset(); offset.Add(opposite) != (Point{}) {
+			t.Errorf("%v moves %v but %v moves %v", d, offset, d.Opposite(), opposite)
+		}
+		if d.Diagonal() != (offset.X != 0 && offset.Y != 0) {
+			t.Errorf("%v moves %v, diagonal %v", d, offset, d.Diagonal())
+		}
+	}
+	if N.Offset() != (Point{0, -1}) || E.Offset() != (Point{1, 0}) {
+		t.Errorf("north moves %v and east %v", N.Offset(), E.Offset())
+	}
+}
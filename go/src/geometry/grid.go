@@ -0,0 +1,132 @@
+package geometry
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Grid is a dense rectangle of cells with the top left one at 0,0
+type Grid[T any] struct {
+	width  int
+	height int
+	cells  []T
+}
+
+func NewGrid[T any](width int, height int) Grid[T] {
+	return Grid[T]{width, height, make([]T, width*height)}
+}
+
+func (g Grid[T]) Width() int {
+	return g.width
+}
+
+func (g Grid[T]) Height() int {
+	return g.height
+}
+
+func (g Grid[T]) Bounds() Box {
+	return Box{Point{0, 0}, Point{g.width - 1, g.height - 1}}
+}
+
+func (g Grid[T]) InBounds(p Point) bool {
+	return p.X >= 0 && p.X < g.width && p.Y >= 0 && p.Y < g.height
+}
+
+// At returns the cell at p, which must be inside the grid
+func (g Grid[T]) At(p Point) T {
+	return g.cells[p.Y*g.width+p.X]
+}
+
+func (g Grid[T]) Set(p Point, value T) {
+	g.cells[p.Y*g.width+p.X] = value
+}
+
+// Points returns every point of the grid, row by row
+func (g Grid[T]) Points() []Point {
+	points := make([]Point, 0, len(g.cells))
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			points = append(points, Point{x, y})
+		}
+	}
+	return points
+}
+
+// Neighbours4 returns the neighbours of p sharing a side that are inside the grid
+func (g Grid[T]) Neighbours4(p Point) []Point {
+	return g.inside(p.Neighbours4())
+}
+
+// Neighbours8 returns the neighbours of p that are inside the grid
+func (g Grid[T]) Neighbours8(p Point) []Point {
+	return g.inside(p.Neighbours8())
+}
+
+func (g Grid[T]) inside(points []Point) []Point {
+	kept := points[:0]
+	for _, p := range points {
+		if g.InBounds(p) {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+// Find returns the first point, row by row, whose cell matches
+func (g Grid[T]) Find(match func(T) bool) (Point, bool) {
+	for i, cell := range g.cells {
+		if match(cell) {
+			return Point{i % g.width, i / g.width}, true
+		}
+	}
+	return Point{}, false
+}
+
+// Format draws the grid a row per line, with a character per cell
+func (g Grid[T]) Format(char func(T) byte) string {
+	var b strings.Builder
+	for y := 0; y < g.height; y++ {
+		for x := 0; x < g.width; x++ {
+			b.WriteByte(char(g.cells[y*g.width+x]))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// ParseGrid reads a grid with a line per row and a character per cell.
+// All the lines must be as long as the first one.
+func ParseGrid[T any](lines []string, cell func(byte) (T, error)) (Grid[T], error) {
+	if len(lines) == 0 || len(lines[0]) == 0 {
+		return Grid[T]{}, fmt.Errorf("empty grid")
+	}
+
+	g := NewGrid[T](len(lines[0]), len(lines))
+	for y, line := range lines {
+		if len(line) != g.width {
+			return Grid[T]{}, fmt.Errorf("line %d has %d characters, expected %d", y+1, len(line), g.width)
+		}
+		for x := 0; x < len(line); x++ {
+			value, err := cell(line[x])
+			if err != nil {
+				return Grid[T]{}, fmt.Errorf("line %d, column %d: %w", y+1, x+1, err)
+			}
+			g.cells[y*g.width+x] = value
+		}
+	}
+	return g, nil
+}
+
+// ParseCharGrid reads a grid keeping the characters as they are
+func ParseCharGrid(lines []string) (Grid[byte], error) {
+	return ParseGrid(lines, func(c byte) (byte, error) {
+		return c, nil
+	})
+}
+
+// FormatCharGrid draws a grid of characters
+func FormatCharGrid(g Grid[byte]) string {
+	return g.Format(func(c byte) byte {
+		return c
+	})
+}
@@ -0,0 +1,101 @@
+package geometry
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGridBounds(t *testing.T) {
+	g, err := ParseCharGrid([]string{"abc", "def"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Width() != 3 || g.Height() != 2 || g.Bounds() != (Box{Point{0, 0}, Point{2, 1}}) {
+		t.Fatalf("grid of %dx%d with bounds %v", g.Width(), g.Height(), g.Bounds())
+	}
+
+	tests := []struct {
+		p    Point
+		want bool
+	}{
+		{Point{0, 0}, true},
+		{Point{2, 1}, true},
+		{Point{1, 1}, true},
+		{Point{0, 2}, false},
+		{Point{-1, 0}, false},
+		{Point{0, -1}, false},
+		// right of the last cell of a row is not the next row
+		{Point{3, 0}, false},
+	}
+	for _, tt := range tests {
+		if got := g.InBounds(tt.p); got != tt.want {
+			t.Errorf("%v in bounds: %v, expected %v", tt.p, got, tt.want)
+		}
+		if got := g.Bounds().Contains(tt.p); got != tt.want {
+			t.Errorf("%v in %v: %v, expected %v", tt.p, g.Bounds(), got, tt.want)
+		}
+	}
+
+	if got := g.At(Point{2, 1}); got != 'f' {
+		t.Errorf("cell 2,1 is %q, expected 'f'", got)
+	}
+	corner := g.Neighbours8(Point{0, 0})
+	if want := []Point{{1, 0}, {1, 1}, {0, 1}}; !slices.Equal(corner, want) {
+		t.Errorf("the corner touches %v, expected %v", corner, want)
+	}
+	if side := g.Neighbours4(Point{1, 1}); len(side) != 3 {
+		t.Errorf("the bottom middle cell has the neighbours %v", side)
+	}
+	if FormatCharGrid(g) != "abc\ndef\n" {
+		t.Errorf("grid is drawn as %q", FormatCharGrid(g))
+	}
+}
+
+func TestParseGridErrors(t *testing.T) {
+	for _, lines := range [][]string{nil, {""}, {"abc", "de"}, {"ab", "cde"}} {
+		if _, err := ParseCharGrid(lines); err == nil {
+			t.Errorf("%q reads as a grid", lines)
+		}
+	}
+}
+
+func TestSparseBounds(t *testing.T) {
+	s := NewSparse[byte]()
+	if _, ok := s.Bounds(); ok {
+		t.Fatal("an empty sparse grid has bounds")
+	}
+
+	steps := []struct {
+		p    Point
+		want Box
+	}{
+		{Point{0, 0}, Box{Point{0, 0}, Point{0, 0}}},
+		{Point{3, -2}, Box{Point{0, -2}, Point{3, 0}}},
+		{Point{-4, 1}, Box{Point{-4, -2}, Point{3, 1}}},
+		// a point inside the box does not grow it
+		{Point{1, -1}, Box{Point{-4, -2}, Point{3, 1}}},
+	}
+	for _, step := range steps {
+		s.Set(step.p, '#')
+		if box, ok := s.Bounds(); !ok || box != step.want {
+			t.Fatalf("after setting %v the bounds are %v, expected %v", step.p, box, step.want)
+		}
+	}
+
+	g, box := s.Dense()
+	if g.Width() != 8 || g.Height() != 4 {
+		t.Fatalf("the dense grid is %dx%d, expected 8x4", g.Width(), g.Height())
+	}
+	if g.At(Point{3, -2}.Sub(box.Min)) != '#' || g.At(Point{0, 0}) != 0 {
+		t.Fatal("the dense grid does not hold the cells in place")
+	}
+
+	s.Delete(Point{-4, 1})
+	if box, _ := s.Bounds(); box != (Box{Point{0, -2}, Point{3, 0}}) || s.Len() != 3 {
+		t.Fatalf("after deleting a corner the bounds are %v with %d cells", box, s.Len())
+	}
+	want := "...#\n.#..\n#...\n"
+	if got := s.Format('.', func(c byte) byte { return c }); got != want {
+		t.Fatalf("drawn as %q, expected %q", got, want)
+	}
+}
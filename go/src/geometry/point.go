@@ -0,0 +1,65 @@
+// Package geometry works with points on a 2D grid, the directions to move
+// between them and grids of cells, like Santa's trail in the Infi puzzle.
+//
+// Y grows to the south, like the rows of a character grid, so that north
+// is at the top when a grid is printed.
+package geometry
+
+// Point is a cell of the grid
+type Point struct {
+	X int
+	Y int
+}
+
+func (p Point) Add(q Point) Point {
+	return Point{p.X + q.X, p.Y + q.Y}
+}
+
+func (p Point) Sub(q Point) Point {
+	return Point{p.X - q.X, p.Y - q.Y}
+}
+
+// Mul scales the point, a direction offset times n is n steps that way
+func (p Point) Mul(n int) Point {
+	return Point{p.X * n, p.Y * n}
+}
+
+// Manhattan is the distance walking only horizontally and vertically
+func (p Point) Manhattan(q Point) int {
+	return abs(p.X-q.X) + abs(p.Y-q.Y)
+}
+
+// Chebyshev is the distance when a diagonal step counts as one step,
+// like a king moving on a chessboard
+func (p Point) Chebyshev(q Point) int {
+	dx, dy := abs(p.X-q.X), abs(p.Y-q.Y)
+	if dx > dy {
+		return dx
+	}
+	return dy
+}
+
+// Neighbours4 returns the points sharing a side with p, clockwise from north
+func (p Point) Neighbours4() []Point {
+	neighbours := make([]Point, 0, 4)
+	for d := North; d < Direction4(4); d++ {
+		neighbours = append(neighbours, p.Add(d.Offset()))
+	}
+	return neighbours
+}
+
+// Neighbours8 returns the points touching p, clockwise from north
+func (p Point) Neighbours8() []Point {
+	neighbours := make([]Point, 0, 8)
+	for d := N; d < Direction8(8); d++ {
+		neighbours = append(neighbours, p.Add(d.Offset()))
+	}
+	return neighbours
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,88 @@
+package geometry
+
+import (
+	"sort"
+	"strings"
+)
+
+// Sparse is a grid without bounds that only stores the cells that were
+// set, for things like a trail wandering far away from the start
+type Sparse[T any] struct {
+	cells map[Point]T
+}
+
+func NewSparse[T any]() *Sparse[T] {
+	return &Sparse[T]{make(map[Point]T)}
+}
+
+func (s *Sparse[T]) Len() int {
+	return len(s.cells)
+}
+
+func (s *Sparse[T]) Set(p Point, value T) {
+	s.cells[p] = value
+}
+
+func (s *Sparse[T]) Get(p Point) (T, bool) {
+	value, ok := s.cells[p]
+	return value, ok
+}
+
+func (s *Sparse[T]) Delete(p Point) {
+	delete(s.cells, p)
+}
+
+// Points returns the points that are set, row by row
+func (s *Sparse[T]) Points() []Point {
+	points := make([]Point, 0, len(s.cells))
+	for p := range s.cells {
+		points = append(points, p)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].Y != points[j].Y {
+			return points[i].Y < points[j].Y
+		}
+		return points[i].X < points[j].X
+	})
+	return points
+}
+
+// Bounds returns the smallest box holding every point that is set
+func (s *Sparse[T]) Bounds() (Box, bool) {
+	return BoundingBox(s.Points())
+}
+
+// Dense copies the cells into a grid just big enough to hold them,
+// the top left corner of the box is 0,0 in the grid
+func (s *Sparse[T]) Dense() (Grid[T], Box) {
+	box, ok := s.Bounds()
+	if !ok {
+		return Grid[T]{}, box
+	}
+	g := NewGrid[T](box.Width(), box.Height())
+	for p, value := range s.cells {
+		g.Set(p.Sub(box.Min), value)
+	}
+	return g, box
+}
+
+// Format draws the bounding box of the cells a row per line,
+// with empty for the cells that are not set
+func (s *Sparse[T]) Format(empty byte, char func(T) byte) string {
+	box, ok := s.Bounds()
+	if !ok {
+		return ""
+	}
+	var b strings.Builder
+	for y := box.Min.Y; y <= box.Max.Y; y++ {
+		for x := box.Min.X; x <= box.Max.X; x++ {
+			if value, ok := s.cells[Point{x, y}]; ok {
+				b.WriteByte(char(value))
+			} else {
+				b.WriteByte(empty)
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
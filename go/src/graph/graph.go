@@ -0,0 +1,79 @@
+// Package graph finds shortest paths in graphs that are never built.
+// A graph is given by a start node and a function returning the
+// neighbours of a node, so grids, puzzle states and trails all work
+// without first listing their nodes and edges.
+package graph
+
+// Edge is a step to a neighbour and what it costs
+type Edge[N comparable] struct {
+	To   N
+	Cost int
+}
+
+// Stats tells how much work a search did
+type Stats struct {
+	// Expanded counts the nodes whose neighbours were looked at
+	Expanded int
+	// Pushed counts the nodes added to the frontier
+	Pushed int
+	// Improved counts the nodes reached again by a cheaper path,
+	// a decrease-key when they were still on the frontier
+	Improved int
+	// MaxFrontier is the largest the frontier got
+	MaxFrontier int
+}
+
+// Result is the outcome of a search. Path goes from the start
+// to the goal, both included, and is empty when Found is false.
+type Result[N comparable] struct {
+	Found bool
+	Cost  int
+	Path  []N
+	Stats Stats
+}
+
+// reconstruct follows the parents back from the goal to the start
+func reconstruct[N comparable](parents map[N]N, start N, goal N) []N {
+	path := []N{goal}
+	for node := goal; node != start; {
+		node = parents[node]
+		path = append(path, node)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// BFS finds the path with the fewest steps from start to a node
+// matching goal, every step costing one
+func BFS[N comparable](start N, neighbours func(N) []N, goal func(N) bool) Result[N] {
+	parents := map[N]N{}
+	seen := map[N]bool{start: true}
+	frontier := []N{start}
+	stats := Stats{Pushed: 1, MaxFrontier: 1}
+
+	for steps := 0; len(frontier) > 0; steps++ {
+		next := []N{}
+		for _, node := range frontier {
+			if goal(node) {
+				return Result[N]{true, steps, reconstruct(parents, start, node), stats}
+			}
+			stats.Expanded++
+			for _, neighbour := range neighbours(node) {
+				if seen[neighbour] {
+					continue
+				}
+				seen[neighbour] = true
+				parents[neighbour] = node
+				next = append(next, neighbour)
+				stats.Pushed++
+			}
+		}
+		frontier = next
+		if len(frontier) > stats.MaxFrontier {
+			stats.MaxFrontier = len(frontier)
+		}
+	}
+	return Result[N]{Stats: stats}
+}
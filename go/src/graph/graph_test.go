@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"geometry"
+	"slices"
+	"testing"
+)
+
+// maze is a small grid with a single shortest way around the walls
+var maze = []string{
+	"S..#.",
+	".#.#.",
+	".#...",
+	"...#G",
+}
+
+// parseMaze reads a grid of walls, #, and finds the S and G cells
+func parseMaze(t *testing.T, lines []string) (geometry.Grid[byte], geometry.Point, geometry.Point) {
+	t.Helper()
+	g, err := geometry.ParseCharGrid(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start, ok := g.Find(func(c byte) bool { return c == 'S' })
+	if !ok {
+		t.Fatal("no start in the maze")
+	}
+	goal, ok := g.Find(func(c byte) bool { return c == 'G' })
+	if !ok {
+		t.Fatal("no goal in the maze")
+	}
+	return g, start, goal
+}
+
+func open(c byte) bool {
+	return c != '#'
+}
+
+// checkPath makes sure the path goes from start to goal through
+// neighbouring open cells
+func checkPath(t *testing.T, g geometry.Grid[byte], path []geometry.Point, start geometry.Point, goal geometry.Point) {
+	t.Helper()
+	if len(path) == 0 || path[0] != start || path[len(path)-1] != goal {
+		t.Fatalf("path %v does not go from %v to %v", path, start, goal)
+	}
+	for i, p := range path {
+		if !g.InBounds(p) || !open(g.At(p)) {
+			t.Fatalf("path goes through %v", p)
+		}
+		if i > 0 && p.Chebyshev(path[i-1]) != 1 {
+			t.Fatalf("path jumps from %v to %v", path[i-1], p)
+		}
+	}
+}
+
+func TestBFS(t *testing.T) {
+	g, start, goal := parseMaze(t, maze)
+	tests := []struct {
+		name     string
+		diagonal bool
+		steps    int
+	}{
+		{"straight", false, 7},
+		{"diagonal", true, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := BFS(start, GridNeighbours(g, open, tt.diagonal), Target(goal))
+			if !result.Found || result.Cost != tt.steps {
+				t.Fatalf("found %v in %d steps, expected %d", result.Found, result.Cost, tt.steps)
+			}
+			if len(result.Path) != tt.steps+1 {
+				t.Fatalf("path %v has %d steps", result.Path, len(result.Path)-1)
+			}
+			checkPath(t, g, result.Path, start, goal)
+		})
+	}
+}
+
+func TestBFSAtGoal(t *testing.T) {
+	g, start, _ := parseMaze(t, maze)
+	result := BFS(start, GridNeighbours(g, open, false), Target(start))
+	if !result.Found || result.Cost != 0 || !slices.Equal(result.Path, []geometry.Point{start}) {
+		t.Fatalf("got %+v, expected the start alone", result)
+	}
+	if result.Stats.Expanded != 0 {
+		t.Fatalf("expanded %d nodes before looking at the start", result.Stats.Expanded)
+	}
+}
+
+func TestUnreachable(t *testing.T) {
+	g, start, goal := parseMaze(t, []string{
+		"S.#..",
+		"..#.G",
+	})
+	neighbours := GridNeighbours(g, open, true)
+	results := map[string]Result[geometry.Point]{
+		"BFS":      BFS(start, neighbours, Target(goal)),
+		"Dijkstra": Dijkstra(start, StepCosts(neighbours), Target(goal)),
+		"A*":       AStar(start, StepCosts(neighbours), Target(goal), Manhattan(goal)),
+	}
+	for name, result := range results {
+		if result.Found || result.Cost != 0 || len(result.Path) != 0 {
+			t.Errorf("%s: got %+v for an unreachable goal", name, result)
+		}
+		// every cell on the side of the start is looked at
+		if result.Stats.Expanded != 4 {
+			t.Errorf("%s: expanded %d cells, expected 4", name, result.Stats.Expanded)
+		}
+	}
+}
+
+func TestReconstruct(t *testing.T) {
+	parents := map[string]string{"b": "a", "c": "b", "d": "c", "x": "a"}
+	if path := reconstruct(parents, "a", "d"); !slices.Equal(path, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("got %v", path)
+	}
+	if path := reconstruct(parents, "a", "a"); !slices.Equal(path, []string{"a"}) {
+		t.Fatalf("got %v", path)
+	}
+}
@@ -0,0 +1,53 @@
+package graph
+
+import "geometry"
+
+// GridNeighbours returns the cells a search can move to from a cell of the
+// grid, the ones for which open is true. Diagonal moves are allowed when
+// diagonal is set.
+func GridNeighbours[T any](g geometry.Grid[T], open func(T) bool, diagonal bool) func(geometry.Point) []geometry.Point {
+	return func(p geometry.Point) []geometry.Point {
+		var candidates []geometry.Point
+		if diagonal {
+			candidates = g.Neighbours8(p)
+		} else {
+			candidates = g.Neighbours4(p)
+		}
+		next := candidates[:0]
+		for _, q := range candidates {
+			if open(g.At(q)) {
+				next = append(next, q)
+			}
+		}
+		return next
+	}
+}
+
+// StepCosts gives the moves of a grid the cost of the Manhattan distance
+// they cover, so a diagonal step costs two like in the Infi puzzle
+func StepCosts(neighbours func(geometry.Point) []geometry.Point) func(geometry.Point) []Edge[geometry.Point] {
+	return func(p geometry.Point) []Edge[geometry.Point] {
+		next := neighbours(p)
+		edges := make([]Edge[geometry.Point], len(next))
+		for i, q := range next {
+			edges[i] = Edge[geometry.Point]{q, p.Manhattan(q)}
+		}
+		return edges
+	}
+}
+
+// Manhattan is the A* heuristic for grids where every step costs the
+// Manhattan distance it covers
+func Manhattan(goal geometry.Point) func(geometry.Point) int {
+	return func(p geometry.Point) int {
+		return p.Manhattan(goal)
+	}
+}
+
+// Chebyshev is the A* heuristic for grids with diagonal steps
+// that cost the same as the others
+func Chebyshev(goal geometry.Point) func(geometry.Point) int {
+	return func(p geometry.Point) int {
+		return p.Chebyshev(goal)
+	}
+}
@@ -0,0 +1,74 @@
+package graph
+
+import "container/heap"
+
+// item is a node on the frontier, index is its place in the heap
+// so that its priority can be lowered without searching for it
+type item[N comparable] struct {
+	node     N
+	priority int
+	index    int
+}
+
+// priorityQueue is a min heap of items for container/heap,
+// like IntMinHeap in day 1 but keeping track of where every item is
+type priorityQueue[N comparable] []*item[N]
+
+func (q priorityQueue[N]) Len() int           { return len(q) }
+func (q priorityQueue[N]) Less(i, j int) bool { return q[i].priority < q[j].priority }
+
+func (q priorityQueue[N]) Swap(i, j int) {
+	q[i], q[j] = q[j], q[i]
+	q[i].index = i
+	q[j].index = j
+}
+
+func (q *priorityQueue[N]) Push(x any) {
+	it := x.(*item[N])
+	it.index = len(*q)
+	*q = append(*q, it)
+}
+
+func (q *priorityQueue[N]) Pop() any {
+	old := *q
+	n := len(old)
+	it := old[n-1]
+	old[n-1] = nil
+	it.index = -1
+	*q = old[0 : n-1]
+	return it
+}
+
+// frontier is the queue together with the items of the nodes on it
+type frontier[N comparable] struct {
+	queue priorityQueue[N]
+	items map[N]*item[N]
+}
+
+func newFrontier[N comparable]() *frontier[N] {
+	return &frontier[N]{items: map[N]*item[N]{}}
+}
+
+func (f *frontier[N]) Len() int {
+	return f.queue.Len()
+}
+
+// push adds the node, or lowers its priority when it is already waiting.
+// It reports whether the node was already on the frontier.
+func (f *frontier[N]) push(node N, priority int) bool {
+	if it, ok := f.items[node]; ok {
+		it.priority = priority
+		heap.Fix(&f.queue, it.index)
+		return true
+	}
+	it := &item[N]{node: node, priority: priority}
+	f.items[node] = it
+	heap.Push(&f.queue, it)
+	return false
+}
+
+func (f *frontier[N]) pop() N {
+	it := heap.Pop(&f.queue).(*item[N])
+	delete(f.items, it.node)
+	return it.node
+}
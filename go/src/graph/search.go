@@ -0,0 +1,72 @@
+package graph
+
+// Dijkstra finds the cheapest path from start to a node matching goal.
+// Costs must not be negative.
+func Dijkstra[N comparable](start N, neighbours func(N) []Edge[N], goal func(N) bool) Result[N] {
+	return AStar(start, neighbours, goal, func(N) int { return 0 })
+}
+
+// AStar finds the cheapest path from start to a node matching goal,
+// looking first at the nodes the heuristic deems closest to the goal.
+//
+// The path is the cheapest one as long as the heuristic never guesses
+// more than the real cost to the goal. With a heuristic that also never
+// drops by more than the cost of a step, like the Manhattan distance on
+// a grid, no node is expanded twice; otherwise a node reached again by a
+// cheaper path goes back on the frontier.
+func AStar[N comparable](start N, neighbours func(N) []Edge[N], goal func(N) bool, heuristic func(N) int) Result[N] {
+	costs := map[N]int{start: 0}
+	parents := map[N]N{}
+	open := newFrontier[N]()
+	open.push(start, heuristic(start))
+	stats := Stats{Pushed: 1, MaxFrontier: 1}
+
+	for open.Len() > 0 {
+		node := open.pop()
+		if goal(node) {
+			return Result[N]{true, costs[node], reconstruct(parents, start, node), stats}
+		}
+
+		stats.Expanded++
+		for _, edge := range neighbours(node) {
+			cost := costs[node] + edge.Cost
+			known, reached := costs[edge.To]
+			if reached && known <= cost {
+				continue
+			}
+			costs[edge.To] = cost
+			parents[edge.To] = node
+
+			if waiting := open.push(edge.To, cost+heuristic(edge.To)); !waiting {
+				stats.Pushed++
+			}
+			if reached {
+				stats.Improved++
+			}
+			if open.Len() > stats.MaxFrontier {
+				stats.MaxFrontier = open.Len()
+			}
+		}
+	}
+	return Result[N]{Stats: stats}
+}
+
+// UnitCosts turns a neighbour function for BFS into one for Dijkstra and
+// A*, with every step costing the same
+func UnitCosts[N comparable](neighbours func(N) []N) func(N) []Edge[N] {
+	return func(node N) []Edge[N] {
+		next := neighbours(node)
+		edges := make([]Edge[N], len(next))
+		for i, to := range next {
+			edges[i] = Edge[N]{to, 1}
+		}
+		return edges
+	}
+}
+
+// Target returns a goal function matching a single node
+func Target[N comparable](target N) func(N) bool {
+	return func(node N) bool {
+		return node == target
+	}
+}
@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"geometry"
+	"slices"
+	"testing"
+)
+
+// risk is a grid where entering a cell costs its digit
+var risk = []string{
+	"116",
+	"138",
+	"213",
+}
+
+func riskCosts(t *testing.T) func(geometry.Point) []Edge[geometry.Point] {
+	t.Helper()
+	g, err := geometry.ParseCharGrid(risk)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func(p geometry.Point) []Edge[geometry.Point] {
+		edges := []Edge[geometry.Point]{}
+		for _, q := range g.Neighbours4(p) {
+			edges = append(edges, Edge[geometry.Point]{q, int(g.At(q) - '0')})
+		}
+		return edges
+	}
+}
+
+func TestWeightedGrid(t *testing.T) {
+	start, goal := geometry.Point{X: 0, Y: 0}, geometry.Point{X: 2, Y: 2}
+	want := []geometry.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 0, Y: 2}, {X: 1, Y: 2}, {X: 2, Y: 2}}
+
+	dijkstra := Dijkstra(start, riskCosts(t), Target(goal))
+	// every step costs at least 1, so the Manhattan distance never guesses too much
+	astar := AStar(start, riskCosts(t), Target(goal), Manhattan(goal))
+	for name, result := range map[string]Result[geometry.Point]{"Dijkstra": dijkstra, "A*": astar} {
+		if !result.Found || result.Cost != 7 {
+			t.Errorf("%s: found %v with cost %d, expected 7", name, result.Found, result.Cost)
+		}
+		if !slices.Equal(result.Path, want) {
+			t.Errorf("%s: path %v, expected %v", name, result.Path, want)
+		}
+	}
+	if astar.Stats.Expanded > dijkstra.Stats.Expanded {
+		t.Errorf("A* expanded %d nodes, more than the %d of Dijkstra", astar.Stats.Expanded, dijkstra.Stats.Expanded)
+	}
+}
+
+func TestGridCosts(t *testing.T) {
+	g, start, goal := parseMaze(t, maze)
+	tests := []struct {
+		name     string
+		diagonal bool
+		cost     int
+	}{
+		{"straight", false, 7},
+		// three diagonal steps of two and a straight one
+		{"diagonal", true, 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			neighbours := StepCosts(GridNeighbours(g, open, tt.diagonal))
+			dijkstra := Dijkstra(start, neighbours, Target(goal))
+			astar := AStar(start, neighbours, Target(goal), Manhattan(goal))
+			for name, result := range map[string]Result[geometry.Point]{"Dijkstra": dijkstra, "A*": astar} {
+				if !result.Found || result.Cost != tt.cost {
+					t.Fatalf("%s: found %v with cost %d, expected %d", name, result.Found, result.Cost, tt.cost)
+				}
+				checkPath(t, g, result.Path, start, goal)
+			}
+		})
+	}
+}
+
+func TestUnitCosts(t *testing.T) {
+	g, start, goal := parseMaze(t, maze)
+	neighbours := GridNeighbours(g, open, true)
+	bfs := BFS(start, neighbours, Target(goal))
+	astar := AStar(start, UnitCosts(neighbours), Target(goal), Chebyshev(goal))
+	if !astar.Found || astar.Cost != bfs.Cost {
+		t.Fatalf("A* found %v with cost %d, BFS took %d steps", astar.Found, astar.Cost, bfs.Cost)
+	}
+	checkPath(t, g, astar.Path, start, goal)
+}
+
+// TestDecreaseKey reaches b first by the expensive direct edge, the
+// cheaper way through c then lowers its priority on the frontier
+func TestDecreaseKey(t *testing.T) {
+	edges := map[string][]Edge[string]{
+		"a": {{"b", 5}, {"c", 1}},
+		"c": {{"b", 1}},
+		"b": {{"d", 1}},
+	}
+	neighbours := func(node string) []Edge[string] {
+		return edges[node]
+	}
+
+	result := Dijkstra("a", neighbours, Target("d"))
+	if !result.Found || result.Cost != 3 {
+		t.Fatalf("found %v with cost %d, expected 3", result.Found, result.Cost)
+	}
+	if want := []string{"a", "c", "b", "d"}; !slices.Equal(result.Path, want) {
+		t.Fatalf("path %v, expected %v", result.Path, want)
+	}
+	want := Stats{Expanded: 3, Pushed: 4, Improved: 1, MaxFrontier: 2}
+	if result.Stats != want {
+		t.Fatalf("stats %+v, expected %+v", result.Stats, want)
+	}
+}
+
+func TestFrontier(t *testing.T) {
+	f := newFrontier[string]()
+	f.push("a", 5)
+	f.push("b", 3)
+	f.push("c", 4)
+	if waiting := f.push("a", 1); !waiting {
+		t.Fatal("a was on the frontier")
+	}
+	if waiting := f.push("d", 2); waiting {
+		t.Fatal("d was not on the frontier")
+	}
+
+	order := []string{}
+	for f.Len() > 0 {
+		order = append(order, f.pop())
+	}
+	if want := []string{"a", "d", "b", "c"}; !slices.Equal(order, want) {
+		t.Fatalf("popped %v, expected %v", order, want)
+	}
+}
@@ -78,141 +78,141 @@ solution. Follow the link below if you want to win.
 package main
 
 import (
-	"bufio"
+	"flag"
 	"fmt"
+	"geometry"
 	"log"
-	"math"
 	"os"
+	"parsing"
 )
 
-type coordinates [2]int
-
+// instruction is a line like "draai 90", decoded by the parsing package
 type instruction struct {
-	command string
-	number  int
-}
-
-var directions = map[string]coordinates{
-	"north":     {0, 1},
-	"south":     {0, -1},
-	"east":      {1, 0},
-	"west":      {-1, 0},
-	"northeast": {1, 1},
-	"southeast": {1, -1},
-	"southwest": {-1, -1},
-	"northwest": {-1, 1},
+	Command string `parse:"command"`
+	Number  int    `parse:"number"`
 }
 
-type direction int
-
-// Using enums to handle the directions and the turns
-// makes it elegant and easy to understand.
-const (
-	north = iota
-	northeast
-	east
-	southeast
-	south
-	southwest
-	west
-	northwest
-)
-
-var directionsOffset = map[direction]coordinates{
-	north:     {0, 1},
-	northeast: {1, 1},
-	east:      {1, 0},
-	southeast: {1, -1},
-	south:     {0, -1},
-	southwest: {-1, -1},
-	west:      {-1, 0},
-	northwest: {-1, 1},
-}
+// maxNumber bounds the numbers of the instructions, far above the
+// puzzle's but low enough that walking takes no time
+const maxNumber = 10000
 
-func degrees2turns(degrees int) int {
-	return degrees / 45
+func parseInstructions(lines []string) ([]instruction, error) {
+	instructions := make([]instruction, len(lines))
+	for i, line := range lines {
+		if err := parsing.Decode(line, "{command} {number}", &instructions[i]); err != nil {
+			return nil, parsing.AtLine(err, i+1)
+		}
+		switch command := instructions[i].Command; command {
+		case "draai", "loop", "spring":
+		default:
+			return nil, &parsing.Error{Line: i + 1, Column: 1, Err: fmt.Errorf("unknown command %q", command)}
+		}
+		if n := instructions[i].Number; n < -maxNumber || n > maxNumber {
+			column := len(instructions[i].Command) + 2
+			return nil, &parsing.Error{Line: i + 1, Column: column, Err: fmt.Errorf("number %d is out of range, at most %d", n, maxNumber)}
+		}
+	}
+	return instructions, nil
 }
 
-func turn(current *direction, degrees int) {
-	degrees %= 360
-	if degrees < 0 {
-		degrees += 360
-	}
-	*current += direction(degrees / 45)
-	*current %= 8
+// Santa turns in steps of 45 degrees, so the directions are the
+// eight compass directions of the geometry package and turning is
+// rotating through them.
+func turn(current geometry.Direction8, degrees int) geometry.Direction8 {
+	return current.Turn(degrees)
 }
 
-func walk(santa *coordinates, current direction, number int, path *[]coordinates) {
+// walk leaves a footprint in the snow on every step
+func walk(santa geometry.Point, current geometry.Direction8, number int, trail *geometry.Sparse[bool]) geometry.Point {
 	for i := 0; i < number; i++ {
-		santa[0] += directionsOffset[current][0]
-		santa[1] += directionsOffset[current][1]
-		*path = append(*path, *santa)
+		santa = santa.Add(current.Offset())
+		trail.Set(santa, true)
 	}
+	return santa
 }
 
-func jump(santa *coordinates, current direction, number int, path *[]coordinates) {
-	santa[0] += directionsOffset[current][0] * number
-	santa[1] += directionsOffset[current][1] * number
+// jump only leaves a footprint where Santa lands
+func jump(santa geometry.Point, current geometry.Direction8, number int, trail *geometry.Sparse[bool]) geometry.Point {
+	santa = santa.Add(current.Offset().Mul(number))
+	trail.Set(santa, true)
+	return santa
 }
 
-func manhattanDist(x1 int, x2 int, y1 int, y2 int) float64 {
-	return math.Abs(float64(x1-x2)) + math.Abs(float64(y1-y2))
-}
+func navigate(instructions []instruction) (geometry.Point, *geometry.Sparse[bool]) {
+	currentDirection := geometry.N
+	santa := geometry.Point{}
+	trail := geometry.NewSparse[bool]()
 
-func printPaths(paths [][]coordinates) {
-	for _, path := range paths {
-		fmt.Println(path)
+	for _, instruction := range instructions {
+		if instruction.Command == "draai" {
+			currentDirection = turn(currentDirection, instruction.Number)
+		} else if instruction.Command == "loop" {
+			santa = walk(santa, currentDirection, instruction.Number, trail)
+		} else if instruction.Command == "spring" {
+			santa = jump(santa, currentDirection, instruction.Number, trail)
+		}
 	}
+	return santa, trail
 }
 
 func partOne(instructions []instruction) {
-	var currentDirection direction = north
-	santa := coordinates{0, 0}
-	allPaths := [][]coordinates{}
-	path := []coordinates{}
+	santa, _ := navigate(instructions)
 
-	for _, instruction := range instructions {
-		if instruction.command == "draai" {
-			turn(&currentDirection, instruction.number)
-		} else if instruction.command == "loop" {
-			walk(&santa, currentDirection, instruction.number, &path)
-		} else if instruction.command == "spring" {
-			jump(&santa, currentDirection, instruction.number, &path)
-			allPaths = append(allPaths, path)
-			path = []coordinates{}
-			path = append(path, santa)
-		}
+	// the geometry package has y growing south, the puzzle north
+	fmt.Printf("Santa is at [%d %d]\n", santa.X, -santa.Y)
+	fmt.Println("Manhattan distance is", santa.Manhattan(geometry.Point{}))
+}
+
+// partTwo prints the trail in the snow, north at the top,
+// instead of plotting the paths with the Python program
+func partTwo(instructions []instruction) {
+	_, trail := navigate(instructions)
+
+	fmt.Println()
+	if box, _ := trail.Bounds(); !fits(box) {
+		fmt.Printf("The trail is too big to draw, %d by %d\n", box.Width(), box.Height())
+		return
 	}
-	allPaths = append(allPaths, path)
-
-	fmt.Println("Santa is at", santa)
-	fmt.Println("Manhattan distance is", manhattanDist(santa[0], 0, santa[1], 0))
-
-	// Used it to print all the paths traversed by Santa
-	// in order to find the word in the snow
-	// by passing it to the Python program
-	//
-	// fmt.Println()
-	// fmt.Println("All Paths:")
-	// printPaths(allPaths)
+	fmt.Print(trail.Format(' ', func(bool) byte { return '#' }))
+}
+
+// maxCells bounds the area of the snow put on a dense grid, which is
+// all of it even when Santa jumps far away
+const maxCells = 1 << 22
+
+func fits(box geometry.Box) bool {
+	w, h := box.Width(), box.Height()
+	return w <= maxCells && h <= maxCells && w*h <= maxCells
 }
 
 func main() {
+	draw := flag.Bool("draw", false, "draw the trail in the snow to read the word of part two")
+	search := flag.Bool("search", false, "find the way back to the start around the letters in the snow")
+	flag.Parse()
+
 	file, err := os.Open("challenge1input.txt")
 	if err != nil {
 		log.Fatal(err)
 	}
 	defer file.Close()
 
-	var command string
-	var number int
-	instructions := []instruction{}
+	lines, err := parsing.ReadLines(file)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		fmt.Sscanf(scanner.Text(), "%s %d", &command, &number)
-		instructions = append(instructions, instruction{command, number})
+	instructions, err := parseInstructions(lines)
+	if err != nil {
+		log.Fatal(err)
 	}
 
 	partOne(instructions)
+	if *draw {
+		partTwo(instructions)
+	}
+
+	if *search {
+		santa, trail := navigate(instructions)
+		searchTrail(santa, trail)
+	}
 }
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"geometry"
+	"os"
+	"parsing"
+	"strings"
+	"testing"
+)
+
+func addInputs(f *testing.F) {
+	f.Add("draai 90\nloop 6\nspring 2\ndraai -45\nloop 2\n")
+	if input, err := os.ReadFile("challenge1input.txt"); err == nil {
+		f.Add(string(input))
+	}
+}
+
+// FuzzNavigate checks that every instruction is either decoded or rejected
+// with its line, and that Santa never ends further away than he walked
+func FuzzNavigate(f *testing.F) {
+	addInputs(f)
+	f.Fuzz(func(t *testing.T, data string) {
+		lines := strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+		instructions, err := parseInstructions(lines)
+		if err != nil {
+			var parseErr *parsing.Error
+			if !errors.As(err, &parseErr) || parseErr.Line < 1 || parseErr.Line > len(lines) {
+				t.Fatalf("error without a line: %v", err)
+			}
+			return
+		}
+
+		steps := 0
+		for _, instruction := range instructions {
+			if instruction.Command == "draai" {
+				continue
+			}
+			if instruction.Command == "spring" {
+				// Santa jumps backwards with a negative number
+				steps += max(instruction.Number, -instruction.Number)
+			} else {
+				// while a negative walk goes nowhere
+				steps += max(instruction.Number, 0)
+			}
+		}
+
+		santa, trail := navigate(instructions)
+		if d := santa.Chebyshev(geometry.Point{}); d > steps {
+			t.Fatalf("Santa is %d away after %d steps", d, steps)
+		}
+		if _, ok := trail.Get(santa); !ok && santa != (geometry.Point{}) {
+			t.Fatalf("no footprint where Santa stands at %v", santa)
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"geometry"
+	"graph"
+)
+
+// snowGrid puts the trail on a dense grid with a border of fresh snow
+// around it, so that a path can go around the letters. The corner of
+// the grid is returned to move between the grid and the trail.
+func snowGrid(santa geometry.Point, trail *geometry.Sparse[bool]) (geometry.Grid[bool], geometry.Point) {
+	box, _ := trail.Bounds()
+	box = box.Extend(geometry.Point{}).Extend(santa)
+	box.Min = box.Min.Sub(geometry.Point{X: 1, Y: 1})
+	box.Max = box.Max.Add(geometry.Point{X: 1, Y: 1})
+
+	g := geometry.NewGrid[bool](box.Width(), box.Height())
+	for _, p := range trail.Points() {
+		g.Set(p.Sub(box.Min), true)
+	}
+	return g, box.Min
+}
+
+func printSearch(name string, result graph.Result[geometry.Point]) {
+	fmt.Printf("%-9s cost %3d, %3d steps, %4d expanded, %4d pushed, %3d improved, frontier up to %d\n",
+		name, result.Cost, len(result.Path)-1, result.Stats.Expanded, result.Stats.Pushed,
+		result.Stats.Improved, result.Stats.MaxFrontier)
+}
+
+// wayBack holds the searches from where Santa ended to the start, on the
+// snow grid whose corner is at corner on the trail
+type wayBack struct {
+	grid     geometry.Grid[bool]
+	corner   geometry.Point
+	start    geometry.Point
+	end      geometry.Point
+	bfs      graph.Result[geometry.Point]
+	dijkstra graph.Result[geometry.Point]
+	astar    graph.Result[geometry.Point]
+}
+
+// findWayBack looks for the way back from where Santa ended to the start
+// without stepping on the letters in the snow. BFS counts the steps, a
+// diagonal one included, while Dijkstra and A* count the Manhattan
+// distance like the puzzle does.
+func findWayBack(santa geometry.Point, trail *geometry.Sparse[bool]) (wayBack, error) {
+	box, _ := trail.Bounds()
+	if box = box.Extend(geometry.Point{}).Extend(santa); !fits(box) {
+		return wayBack{}, fmt.Errorf("the trail is too big to search, %d by %d", box.Width(), box.Height())
+	}
+	g, corner := snowGrid(santa, trail)
+	start, end := santa.Sub(corner), geometry.Point{}.Sub(corner)
+	// both ends are footprints, Santa walked over the start later on
+	g.Set(start, false)
+	g.Set(end, false)
+
+	fresh := func(footprint bool) bool { return !footprint }
+	neighbours := graph.GridNeighbours(g, fresh, true)
+
+	return wayBack{
+		grid:     g,
+		corner:   corner,
+		start:    start,
+		end:      end,
+		bfs:      graph.BFS(start, neighbours, graph.Target(end)),
+		dijkstra: graph.Dijkstra(start, graph.StepCosts(neighbours), graph.Target(end)),
+		astar:    graph.AStar(start, graph.StepCosts(neighbours), graph.Target(end), graph.Manhattan(end)),
+	}, nil
+}
+
+// searchTrail prints the searches for the way back and the route of A*
+func searchTrail(santa geometry.Point, trail *geometry.Sparse[bool]) {
+	w, err := findWayBack(santa, trail)
+	if err != nil {
+		fmt.Println("Cannot search:", err)
+		return
+	}
+	g, start, end := w.grid, w.start, w.end
+	bfs, dijkstra, astar := w.bfs, w.dijkstra, w.astar
+	if !bfs.Found || !dijkstra.Found || !astar.Found {
+		fmt.Println("Santa cannot get back without walking over the letters")
+		return
+	}
+
+	fmt.Println()
+	printSearch("BFS", bfs)
+	printSearch("Dijkstra", dijkstra)
+	printSearch("A*", astar)
+	if dijkstra.Cost != astar.Cost {
+		fmt.Println("Dijkstra and A* disagree on the cost")
+	}
+
+	route := geometry.NewGrid[byte](g.Width(), g.Height())
+	for _, p := range g.Points() {
+		route.Set(p, ' ')
+		if g.At(p) {
+			route.Set(p, '#')
+		}
+	}
+	for _, p := range astar.Path {
+		route.Set(p, '.')
+	}
+	route.Set(start, 'S')
+	route.Set(end, 'E')
+	fmt.Println()
+	fmt.Print(geometry.FormatCharGrid(route))
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"geometry"
+	"graph"
+	"os"
+	"parsing"
+	"testing"
+)
+
+// TestTrailSearch finds the way back from Santa to the start around the
+// letters of the committed input. Steps cost the Manhattan distance they
+// cover, so a diagonal step costs two.
+func TestTrailSearch(t *testing.T) {
+	file, err := os.Open("challenge1input.txt")
+	if err != nil {
+		t.Skip(err)
+	}
+	defer file.Close()
+	lines, err := parsing.ReadLines(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	instructions, err := parseInstructions(lines)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	santa, trail := navigate(instructions)
+	w, err := findWayBack(santa, trail)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !w.bfs.Found || w.bfs.Cost != 32 {
+		t.Errorf("BFS found %v in %d steps, expected 32", w.bfs.Found, w.bfs.Cost)
+	}
+	for name, result := range map[string]graph.Result[geometry.Point]{"Dijkstra": w.dijkstra, "A*": w.astar} {
+		if !result.Found || result.Cost != 40 {
+			t.Errorf("%s found %v with cost %d, expected 40", name, result.Found, result.Cost)
+		}
+		if len(result.Path) < 2 || result.Path[0] != w.start || result.Path[len(result.Path)-1] != w.end {
+			t.Fatalf("%s path %v does not go from %v to %v", name, result.Path, w.start, w.end)
+		}
+		for _, p := range result.Path[1 : len(result.Path)-1] {
+			if w.grid.At(p) {
+				t.Fatalf("%s walks over the letters at %v", name, p.Add(w.corner))
+			}
+		}
+	}
+	if w.start.Add(w.corner) != santa || w.end.Add(w.corner) != (geometry.Point{}) {
+		t.Errorf("the search goes from %v to %v, expected from Santa at %v to the start", w.start.Add(w.corner), w.end.Add(w.corner), santa)
+	}
+	if w.astar.Stats.Expanded >= w.dijkstra.Stats.Expanded {
+		t.Errorf("A* expanded %d nodes, Dijkstra %d", w.astar.Stats.Expanded, w.dijkstra.Stats.Expanded)
+	}
+}
+
+// TestTrailTooBig checks that a trail too big for a grid is not searched
+func TestTrailTooBig(t *testing.T) {
+	trail := geometry.NewSparse[bool]()
+	trail.Set(geometry.Point{X: maxCells, Y: 0}, true)
+	if _, err := findWayBack(geometry.Point{X: maxCells, Y: 0}, trail); err == nil {
+		t.Fatal("searched a trail wider than the grids can be")
+	}
+}
@@ -0,0 +1,177 @@
+// Package interval works with closed ranges of integers,
+// like the section assignments of the Elves in day 4.
+package interval
+
+import "sort"
+
+// Interval is the closed range Start..End, both ends included
+type Interval struct {
+	Start int
+	End   int
+}
+
+// Heat is a stretch of sections covered by the same number of intervals
+type Heat struct {
+	Interval
+	Count int
+}
+
+// Len returns the number of sections in the interval
+func (a Interval) Len() int {
+	if a.End < a.Start {
+		return 0
+	}
+	return a.End - a.Start + 1
+}
+
+// Overlaps reports whether the two intervals share a section,
+// an empty interval overlaps nothing
+func (a Interval) Overlaps(b Interval) bool {
+	return a.Len() > 0 && b.Len() > 0 && a.Start <= b.End && a.End >= b.Start
+}
+
+// Contains reports whether b lies completely inside a, an empty
+// interval contains nothing and lies inside nothing
+func (a Interval) Contains(b Interval) bool {
+	return a.Len() > 0 && b.Len() > 0 && a.Start <= b.Start && a.End >= b.End
+}
+
+// Span returns the smallest interval containing all the intervals,
+// empty intervals are left out
+func Span(intervals []Interval) (Interval, bool) {
+	found := false
+	var span Interval
+	for _, a := range intervals {
+		if a.Len() == 0 {
+			continue
+		}
+		if !found {
+			span, found = a, true
+			continue
+		}
+		if a.Start < span.Start {
+			span.Start = a.Start
+		}
+		if a.End > span.End {
+			span.End = a.End
+		}
+	}
+	return span, found
+}
+
+// Merge returns the union of the intervals as sorted disjoint intervals.
+// Intervals that overlap or touch (like 2-4 and 5-7) are merged,
+// empty intervals are left out.
+func Merge(intervals []Interval) []Interval {
+	sorted := make([]Interval, 0, len(intervals))
+	for _, a := range intervals {
+		if a.Len() > 0 {
+			sorted = append(sorted, a)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	merged := []Interval{}
+	for _, a := range sorted {
+		last := len(merged) - 1
+		if last >= 0 && a.Start <= merged[last].End+1 {
+			if a.End > merged[last].End {
+				merged[last].End = a.End
+			}
+			continue
+		}
+		merged = append(merged, a)
+	}
+	return merged
+}
+
+// Coverage returns the number of sections covered by at least one interval
+func Coverage(intervals []Interval) int {
+	total := 0
+	for _, a := range Merge(intervals) {
+		total += a.Len()
+	}
+	return total
+}
+
+// Gaps returns the sections of within that no interval covers
+func Gaps(intervals []Interval, within Interval) []Interval {
+	gaps := []Interval{}
+	next := within.Start
+	for _, a := range Merge(intervals) {
+		if a.End < within.Start || a.Start > within.End {
+			continue
+		}
+		if a.Start > next {
+			gaps = append(gaps, Interval{next, a.Start - 1})
+		}
+		if a.End+1 > next {
+			next = a.End + 1
+		}
+	}
+	if next <= within.End {
+		gaps = append(gaps, Interval{next, within.End})
+	}
+	return gaps
+}
+
+// Sweep splits the span of the intervals into stretches of constant
+// coverage, in order. Stretches covered by nobody have a zero count.
+//
+// Every interval opens at Start and closes after End, so sorting these
+// events and walking through them gives the coverage in O(n log n).
+func Sweep(intervals []Interval) []Heat {
+	type event struct {
+		at    int
+		delta int
+	}
+	events := make([]event, 0, 2*len(intervals))
+	for _, a := range intervals {
+		if a.Len() == 0 {
+			continue
+		}
+		events = append(events, event{a.Start, 1}, event{a.End + 1, -1})
+	}
+	sort.Slice(events, func(i, j int) bool {
+		return events[i].at < events[j].at
+	})
+
+	heat := []Heat{}
+	count := 0
+	for i := 0; i < len(events); {
+		at := events[i].at
+		for i < len(events) && events[i].at == at {
+			count += events[i].delta
+			i++
+		}
+		if i == len(events) {
+			break
+		}
+		next := events[i].at
+		if last := len(heat) - 1; last >= 0 && heat[last].Count == count {
+			heat[last].End = next - 1
+			continue
+		}
+		heat = append(heat, Heat{Interval{at, next - 1}, count})
+	}
+	return heat
+}
+
+// MostContested returns the highest number of intervals covering a
+// section and the stretches of sections covered that many times
+func MostContested(intervals []Interval) (int, []Interval) {
+	most := 0
+	stretches := []Interval{}
+	for _, h := range Sweep(intervals) {
+		if h.Count > most {
+			most = h.Count
+			stretches = stretches[:0]
+		}
+		if h.Count == most && most > 0 {
+			stretches = append(stretches, h.Interval)
+		}
+	}
+	return most, stretches
+}
@@ -0,0 +1,106 @@
+package interval
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOverlapsContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        Interval
+		b        Interval
+		overlaps bool
+		contains bool
+	}{
+		{"same", Interval{2, 4}, Interval{2, 4}, true, true},
+		{"inside", Interval{2, 8}, Interval{3, 7}, true, true},
+		{"around", Interval{3, 7}, Interval{2, 8}, true, false},
+		{"sharing an end", Interval{5, 7}, Interval{7, 9}, true, false},
+		{"adjacent", Interval{2, 3}, Interval{4, 5}, false, false},
+		{"apart", Interval{2, 4}, Interval{6, 8}, false, false},
+		{"single point inside", Interval{4, 6}, Interval{6, 6}, true, true},
+		{"single point around", Interval{6, 6}, Interval{4, 6}, true, false},
+		{"single points", Interval{6, 6}, Interval{6, 6}, true, true},
+		{"single point outside", Interval{4, 6}, Interval{7, 7}, false, false},
+		{"empty inside", Interval{0, 10}, Interval{8, 7}, false, false},
+		{"empty around", Interval{8, 7}, Interval{0, 10}, false, false},
+		{"empty at an end", Interval{1, 0}, Interval{0, 1}, false, false},
+		{"both empty", Interval{1, 0}, Interval{1, 0}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Overlaps(tt.b); got != tt.overlaps {
+				t.Errorf("%v overlaps %v: %v, expected %v", tt.a, tt.b, got, tt.overlaps)
+			}
+			if got := tt.b.Overlaps(tt.a); got != tt.overlaps {
+				t.Errorf("%v overlaps %v: %v, expected %v", tt.b, tt.a, got, tt.overlaps)
+			}
+			if got := tt.a.Contains(tt.b); got != tt.contains {
+				t.Errorf("%v contains %v: %v, expected %v", tt.a, tt.b, got, tt.contains)
+			}
+		})
+	}
+}
+
+func TestLen(t *testing.T) {
+	tests := []struct {
+		a    Interval
+		want int
+	}{
+		{Interval{2, 4}, 3},
+		{Interval{6, 6}, 1},
+		{Interval{1, 0}, 0},
+		{Interval{8, 3}, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.a.Len(); got != tt.want {
+			t.Errorf("%v has %d sections, expected %d", tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []Interval
+		want      []Interval
+	}{
+		{"adjacent", []Interval{{4, 5}, {2, 3}}, []Interval{{2, 5}}},
+		{"overlapping", []Interval{{2, 6}, {4, 8}}, []Interval{{2, 8}}},
+		{"apart", []Interval{{6, 8}, {2, 4}}, []Interval{{2, 4}, {6, 8}}},
+		{"single points", []Interval{{6, 6}, {5, 5}, {8, 8}}, []Interval{{5, 6}, {8, 8}}},
+		{"empty", []Interval{{1, 0}, {3, 3}, {9, 2}}, []Interval{{3, 3}}},
+		{"only empty", []Interval{{1, 0}}, []Interval{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Merge(tt.intervals); !slices.Equal(got, tt.want) {
+				t.Errorf("merging %v gives %v, expected %v", tt.intervals, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpan(t *testing.T) {
+	tests := []struct {
+		name      string
+		intervals []Interval
+		want      Interval
+		found     bool
+	}{
+		{"example", []Interval{{2, 4}, {6, 8}, {5, 7}}, Interval{2, 8}, true},
+		{"single point", []Interval{{6, 6}}, Interval{6, 6}, true},
+		{"empty left out", []Interval{{1, 0}, {3, 4}, {20, 10}}, Interval{3, 4}, true},
+		{"only empty", []Interval{{1, 0}}, Interval{}, false},
+		{"none", nil, Interval{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, found := Span(tt.intervals)
+			if got != tt.want || found != tt.found {
+				t.Errorf("span of %v is %v (%v), expected %v (%v)", tt.intervals, got, found, tt.want, tt.found)
+			}
+		})
+	}
+}
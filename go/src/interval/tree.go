@@ -0,0 +1,300 @@
+package interval
+
+// Entry is an interval stored in a Tree with the ID it was inserted with,
+// several entries may share an interval as long as their IDs differ
+type Entry struct {
+	Interval
+	ID int
+}
+
+// Tree indexes intervals for point and range queries while
+// intervals come and go. The zero value is an empty tree.
+//
+// Two structures share the work. A segment tree over the section IDs
+// stores every interval at the O(log U) nodes that make it up, U being
+// the span of the IDs seen so far, so the intervals covering a point are
+// exactly those stored on its path from the root. A balanced search tree
+// keeps the intervals sorted by start. The intervals meeting lo..hi are
+// then the ones covering lo and the ones starting in lo+1..hi, two
+// disjoint sets that are found in O(log U + k) and O(log n + k).
+type Tree struct {
+	segments *segment
+	lo       int
+	hi       int
+	starts   *node
+	size     int
+}
+
+// Len returns the number of entries in the tree
+func (t *Tree) Len() int {
+	return t.size
+}
+
+// Insert adds the interval with the given ID and reports whether it was
+// new. Empty intervals cover nothing and are not stored.
+func (t *Tree) Insert(a Interval, id int) bool {
+	if a.Len() == 0 {
+		return false
+	}
+	e := Entry{a, id}
+	var added bool
+	t.starts, added = t.starts.insert(e)
+	if !added {
+		return false
+	}
+
+	t.grow(a)
+	t.segments = t.segments.insert(t.lo, t.hi, e)
+	t.size++
+	return true
+}
+
+// Delete removes the interval with the given ID and reports whether it
+// was in the tree
+func (t *Tree) Delete(a Interval, id int) bool {
+	e := Entry{a, id}
+	var removed bool
+	t.starts, removed = t.starts.remove(e)
+	if !removed {
+		return false
+	}
+
+	t.segments = t.segments.remove(t.lo, t.hi, e)
+	t.size--
+	return true
+}
+
+// Stab returns the entries containing the point, in no particular order
+func (t *Tree) Stab(point int) []Entry {
+	found := []Entry{}
+	lo, hi := t.lo, t.hi
+	for s := t.segments; s != nil && point >= lo && point <= hi; {
+		for e := range s.entries {
+			found = append(found, e)
+		}
+		mid := lo + (hi-lo)/2
+		if point <= mid {
+			s, hi = s.left, mid
+		} else {
+			s, lo = s.right, mid+1
+		}
+	}
+	return found
+}
+
+// Intersecting returns the entries sharing a section with the
+// interval, in no particular order
+func (t *Tree) Intersecting(a Interval) []Entry {
+	if a.Len() == 0 {
+		return []Entry{}
+	}
+	return t.starts.collect(a.Start+1, a.End, t.Stab(a.Start))
+}
+
+// grow doubles the range of the segment tree until it holds the interval.
+// The old root becomes a child of the new one and every interval keeps
+// the same nodes, as it lies inside the old range.
+func (t *Tree) grow(a Interval) {
+	if t.segments == nil {
+		t.lo, t.hi = a.Start, a.Start
+	}
+	for a.Start < t.lo || a.End > t.hi {
+		width := t.hi - t.lo + 1
+		root := &segment{}
+		if t.segments != nil {
+			root.count = t.segments.count
+		}
+		if a.Start < t.lo {
+			root.right = t.segments
+			t.lo -= width
+		} else {
+			root.left = t.segments
+			t.hi += width
+		}
+		t.segments = root
+	}
+}
+
+// segment is a node of the segment tree, covering a range of sections
+// that is only known while walking down from the root
+type segment struct {
+	left    *segment
+	right   *segment
+	entries map[Entry]bool
+	// count is the number of entries stored in the subtree,
+	// a subtree is dropped once it is empty
+	count int
+}
+
+func (s *segment) insert(lo int, hi int, e Entry) *segment {
+	if s == nil {
+		s = &segment{}
+	}
+	s.count++
+	if e.Start <= lo && e.End >= hi {
+		if s.entries == nil {
+			s.entries = make(map[Entry]bool)
+		}
+		s.entries[e] = true
+		return s
+	}
+
+	mid := lo + (hi-lo)/2
+	if e.Start <= mid {
+		s.left = s.left.insert(lo, mid, e)
+	}
+	if e.End > mid {
+		s.right = s.right.insert(mid+1, hi, e)
+	}
+	return s
+}
+
+func (s *segment) remove(lo int, hi int, e Entry) *segment {
+	s.count--
+	if e.Start <= lo && e.End >= hi {
+		delete(s.entries, e)
+	} else {
+		mid := lo + (hi-lo)/2
+		if e.Start <= mid {
+			s.left = s.left.remove(lo, mid, e)
+		}
+		if e.End > mid {
+			s.right = s.right.remove(mid+1, hi, e)
+		}
+	}
+	if s.count == 0 {
+		return nil
+	}
+	return s
+}
+
+// node is a node of the AVL tree ordering the entries by start
+type node struct {
+	entry  Entry
+	left   *node
+	right  *node
+	height int
+}
+
+func less(a Entry, b Entry) bool {
+	if a.Start != b.Start {
+		return a.Start < b.Start
+	}
+	if a.End != b.End {
+		return a.End < b.End
+	}
+	return a.ID < b.ID
+}
+
+func (n *node) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *node) update() {
+	n.height = n.left.getHeight() + 1
+	if h := n.right.getHeight() + 1; h > n.height {
+		n.height = h
+	}
+}
+
+func (n *node) rotateLeft() *node {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+func (n *node) rotateRight() *node {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+// balance restores the AVL property after an insert or a removal below n
+func (n *node) balance() *node {
+	n.update()
+	switch diff := n.left.getHeight() - n.right.getHeight(); {
+	case diff > 1:
+		if n.left.left.getHeight() < n.left.right.getHeight() {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case diff < -1:
+		if n.right.right.getHeight() < n.right.left.getHeight() {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
+func (n *node) insert(e Entry) (*node, bool) {
+	if n == nil {
+		return &node{entry: e, height: 1}, true
+	}
+	var added bool
+	switch {
+	case less(e, n.entry):
+		n.left, added = n.left.insert(e)
+	case less(n.entry, e):
+		n.right, added = n.right.insert(e)
+	default:
+		return n, false
+	}
+	return n.balance(), added
+}
+
+func (n *node) remove(e Entry) (*node, bool) {
+	if n == nil {
+		return nil, false
+	}
+	var removed bool
+	switch {
+	case less(e, n.entry):
+		n.left, removed = n.left.remove(e)
+	case less(n.entry, e):
+		n.right, removed = n.right.remove(e)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// take the place of the next entry
+		next := n.right
+		for next.left != nil {
+			next = next.left
+		}
+		n.entry = next.entry
+		n.right, _ = n.right.remove(next.entry)
+		removed = true
+	}
+	return n.balance(), removed
+}
+
+// collect adds the entries starting in from..to, in order. Only the
+// two paths to the ends of the range and the entries found are visited.
+func (n *node) collect(from int, to int, found []Entry) []Entry {
+	if n == nil {
+		return found
+	}
+	if n.entry.Start >= from {
+		found = n.left.collect(from, to, found)
+	}
+	if n.entry.Start >= from && n.entry.Start <= to {
+		found = append(found, n.entry)
+	}
+	if n.entry.Start <= to {
+		found = n.right.collect(from, to, found)
+	}
+	return found
+}
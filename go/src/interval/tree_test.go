@@ -0,0 +1,114 @@
+package interval
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkBalance returns the height of the subtree, making sure the
+// entries are in order, the heights are right and no node leans more
+// than one level to a side
+func checkBalance(t *testing.T, n *node) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	left, right := checkBalance(t, n.left), checkBalance(t, n.right)
+	if n.left != nil && !less(n.left.entry, n.entry) || n.right != nil && !less(n.entry, n.right.entry) {
+		t.Fatalf("%v is out of order with its children", n.entry)
+	}
+	if left-right > 1 || right-left > 1 {
+		t.Fatalf("%v has subtrees of height %d and %d", n.entry, left, right)
+	}
+	if n.height != max(left, right)+1 {
+		t.Fatalf("%v has height %d, expected %d", n.entry, n.height, max(left, right)+1)
+	}
+	return n.height
+}
+
+func sortEntries(entries []Entry) []Entry {
+	slices.SortFunc(entries, func(a Entry, b Entry) int {
+		return a.ID - b.ID
+	})
+	return entries
+}
+
+// TestTreeRandom inserts and deletes random intervals and compares the
+// queries with a scan of the intervals in the tree
+func TestTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := &Tree{}
+	stored := map[int]Interval{}
+	for step := 0; step < 4000; step++ {
+		id := r.Intn(200)
+		if a, ok := stored[id]; ok && r.Intn(2) == 0 {
+			if !tree.Delete(a, id) {
+				t.Fatalf("step %d: %v with ID %d was not deleted", step, a, id)
+			}
+			if tree.Delete(a, id) {
+				t.Fatalf("step %d: %v with ID %d was deleted twice", step, a, id)
+			}
+			delete(stored, id)
+		} else if !ok {
+			start := r.Intn(400) - 200
+			a := Interval{start, start + r.Intn(40) - 2}
+			if added := tree.Insert(a, id); added != (a.Len() > 0) {
+				t.Fatalf("step %d: inserting %v gives %v", step, a, added)
+			}
+			if a.Len() > 0 {
+				stored[id] = a
+			}
+		}
+
+		if tree.Len() != len(stored) {
+			t.Fatalf("step %d: %d entries, expected %d", step, tree.Len(), len(stored))
+		}
+		checkBalance(t, tree.starts)
+
+		point := r.Intn(480) - 240
+		start := r.Intn(480) - 240
+		query := Interval{start, start + r.Intn(60) - 5}
+		var stabbed, intersecting []Entry
+		for id, a := range stored {
+			if a.Contains(Interval{point, point}) {
+				stabbed = append(stabbed, Entry{a, id})
+			}
+			if a.Overlaps(query) {
+				intersecting = append(intersecting, Entry{a, id})
+			}
+		}
+		if got := sortEntries(tree.Stab(point)); !slices.Equal(got, sortEntries(stabbed)) {
+			t.Fatalf("step %d: stabbing %d gives %v, expected %v", step, point, got, stabbed)
+		}
+		if got := sortEntries(tree.Intersecting(query)); !slices.Equal(got, sortEntries(intersecting)) {
+			t.Fatalf("step %d: %v intersects %v, expected %v", step, query, got, intersecting)
+		}
+	}
+}
+
+// TestTreeDeleteAll empties the tree in a different order than it was
+// filled, the tree must stay balanced and answer nothing once empty
+func TestTreeDeleteAll(t *testing.T) {
+	tree := &Tree{}
+	for id := 0; id < 100; id++ {
+		tree.Insert(Interval{id, id + 10}, id)
+	}
+	if height := checkBalance(t, tree.starts); height > 8 {
+		t.Fatalf("100 entries in a tree of height %d", height)
+	}
+	for id := 0; id < 100; id += 2 {
+		tree.Delete(Interval{id, id + 10}, id)
+		checkBalance(t, tree.starts)
+	}
+	for id := 99; id > 0; id -= 2 {
+		tree.Delete(Interval{id, id + 10}, id)
+		checkBalance(t, tree.starts)
+	}
+	if tree.Len() != 0 || tree.starts != nil || tree.segments != nil {
+		t.Fatalf("%d entries left after deleting them all", tree.Len())
+	}
+	if found := tree.Intersecting(Interval{-1000, 1000}); len(found) != 0 {
+		t.Fatalf("an empty tree finds %v", found)
+	}
+}
@@ -0,0 +1,53 @@
+// Package parsing reads the line based formats of the puzzles: lines
+// following a pattern like "move {n} from {n} to {n}", the integers in a
+// line, records separated by blank lines and lines decoded into structs.
+//
+// Errors carry the line and the column where parsing went wrong, so that
+// a bad input points straight at the offending character.
+package parsing
+
+import "fmt"
+
+// Error is a parsing error at a position in the input. Line and Column
+// start at 1, a zero Line means the line is not known, like when a single
+// line is parsed on its own, and a zero Column means the whole line.
+type Error struct {
+	Line   int
+	Column int
+	Err    error
+}
+
+func (e *Error) Error() string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("line %d, column %d: %v", e.Line, e.Column, e.Err)
+	case e.Line > 0:
+		return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+	case e.Column > 0:
+		return fmt.Sprintf("column %d: %v", e.Column, e.Err)
+	}
+	return e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func errorAt(column int, format string, args ...any) *Error {
+	return &Error{Column: column, Err: fmt.Errorf(format, args...)}
+}
+
+// AtLine places the error on a line of the input. Errors from this
+// package keep their column, any other error applies to the whole line.
+func AtLine(err error, line int) error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(*Error); ok {
+		if e.Line > 0 {
+			return e
+		}
+		return &Error{Line: line, Column: e.Column, Err: e.Err}
+	}
+	return &Error{Line: line, Err: err}
+}
@@ -0,0 +1,149 @@
+package parsing
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// FuzzScan matches lines against the day 5 pattern. A match must survive
+// being written back, a mismatch must point at a column of the line.
+func FuzzScan(f *testing.F) {
+	f.Add("move 1 from 2 to 1")
+	f.Add("move 13 from 8 to 7")
+	f.Add("move -3 from +2 to 0")
+	f.Add("move 1 from 2 to")
+	f.Add("move 99999999999999999999 from 1 to 2")
+	f.Fuzz(func(t *testing.T, line string) {
+		var quantity, from, to int
+		err := Scan(line, "move {n} from {n} to {n}", &quantity, &from, &to)
+		if err != nil {
+			var e *Error
+			if !errors.As(err, &e) || e.Column < 1 || e.Column > len(line)+1 {
+				t.Fatalf("%q: %v is not at a column of the line", line, err)
+			}
+			return
+		}
+
+		again := fmt.Sprintf("move %d from %d to %d", quantity, from, to)
+		var quantity2, from2, to2 int
+		if err := Scan(again, "move {n} from {n} to {n}", &quantity2, &from2, &to2); err != nil {
+			t.Fatalf("%q: cannot read back %q: %v", line, again, err)
+		}
+		if quantity2 != quantity || from2 != from || to2 != to {
+			t.Fatalf("%q: read back %d %d %d from %q", line, quantity2, from2, to2, again)
+		}
+	})
+}
+
+// FuzzDecode compiles any pattern and decodes any line with it, which may
+// fail but never panic
+func FuzzDecode(f *testing.F) {
+	f.Add("{command} {number}", "draai 90")
+	f.Add("{s}: {n}-{n} {c}", "a: 1-3 b")
+	f.Add("{{{n}}}", "{42}")
+	f.Fuzz(func(t *testing.T, pattern string, line string) {
+		if _, err := Compile(pattern); err != nil {
+			return
+		}
+		var v struct {
+			S       string
+			N       int
+			C       byte
+			Small   int8
+			Command string `parse:"command"`
+			Number  int    `parse:"number"`
+		}
+		err := Decode(line, pattern, &v)
+		var e *Error
+		if errors.As(err, &e) && (e.Column < 1 || e.Column > len(line)+1) {
+			t.Fatalf("%q with %q: %v is not at a column of the line", line, pattern, err)
+		}
+	})
+}
+
+// FuzzInts checks that Ints finds a number for every run of digits,
+// reads back the numbers it wrote and points at the numbers too large
+// for an int
+func FuzzInts(f *testing.F) {
+	f.Add("2-4,6-8")
+	f.Add("move 3 from -1 to 2")
+	f.Add(" 1   2   3 ")
+	f.Add("x-5 -7--8")
+	f.Add("-9223372036854775808 9223372036854775808")
+	f.Fuzz(func(t *testing.T, line string) {
+		ints, err := Ints(line)
+		if err != nil {
+			var e *Error
+			if !errors.As(err, &e) || e.Column < 1 || e.Column > len(line) {
+				t.Fatalf("%q: %v is not at a column of the line", line, err)
+			}
+			number := strings.TrimPrefix(line[e.Column-1:], "-")
+			number = number[:len(number)-len(strings.TrimLeft(number, "0123456789"))]
+			if len(strings.TrimLeft(number, "0")) < 19 {
+				t.Fatalf("%q: %v, but %s fits in an int", line, err, number)
+			}
+			return
+		}
+		runs := strings.FieldsFunc(line, func(r rune) bool {
+			return r < '0' || r > '9'
+		})
+		if len(ints) != len(runs) {
+			t.Fatalf("%q: %d numbers for %d runs of digits", line, len(ints), len(runs))
+		}
+
+		written := make([]string, len(ints))
+		for i, n := range ints {
+			written[i] = strconv.Itoa(n)
+		}
+		again, err := Ints(strings.Join(written, " "))
+		if err != nil || !slices.Equal(again, ints) {
+			t.Fatalf("%q: read %v, then %v (%v)", line, ints, again, err)
+		}
+	})
+}
+
+// FuzzRecords checks that the records hold every line that is not blank,
+// with the right line numbers
+func FuzzRecords(f *testing.F) {
+	f.Add("1000\n2000\n\n4000\n")
+	f.Add("\n\n1\n \n\n2\n3")
+	f.Add("a\r\nb\r\n\r\nc")
+	f.Fuzz(func(t *testing.T, data string) {
+		lines, err := ReadLines(strings.NewReader(data))
+		if err != nil {
+			return
+		}
+
+		records := NewRecordScanner(strings.NewReader(data))
+		found := 0
+		for records.Scan() {
+			record := records.Record()
+			for i, line := range record.Lines {
+				if strings.TrimSpace(line) == "" {
+					t.Fatalf("blank line %d in a record", record.Line+i)
+				}
+				if line != lines[record.Line+i-1] {
+					t.Fatalf("line %d is %q, expected %q", record.Line+i, line, lines[record.Line+i-1])
+				}
+			}
+			found += len(record.Lines)
+		}
+		if err := records.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		want := 0
+		for _, line := range lines {
+			if strings.TrimSpace(line) != "" {
+				want++
+			}
+		}
+		if found != want {
+			t.Fatalf("%d lines in records, expected %d", found, want)
+		}
+	})
+}
@@ -0,0 +1,58 @@
+package parsing
+
+import "strconv"
+
+// Ints returns every integer in the line, in order, skipping whatever
+// is around them. A minus sign counts only when it does not follow a
+// digit or a letter, so that 2-4 reads as 2 and 4 and not 2 and -4.
+// It reads the bytes directly, which is a lot faster than a regular
+// expression or splitting the line. A number too large for an int is
+// an error at its column.
+func Ints(line string) ([]int, error) {
+	ints := []int{}
+	for i := 0; i < len(line); {
+		c := line[i]
+		negative := c == '-' && i+1 < len(line) && isDigit(line[i+1]) && (i == 0 || !isWord(line[i-1]))
+		if !negative && !isDigit(c) {
+			i++
+			continue
+		}
+		start := i
+		if negative {
+			i++
+		}
+
+		n := 0
+		overflow := false
+		for ; i < len(line) && isDigit(line[i]); i++ {
+			d := int(line[i] - '0')
+			if n > (maxInt-d)/10 {
+				overflow = true
+			}
+			n = n*10 + d
+		}
+		if overflow {
+			// the smallest int has no positive counterpart
+			if n, err := strconv.Atoi(line[start:i]); err == nil {
+				ints = append(ints, n)
+				continue
+			}
+			return nil, errorAt(start+1, "number %s is out of range", line[start:i])
+		}
+		if negative {
+			n = -n
+		}
+		ints = append(ints, n)
+	}
+	return ints, nil
+}
+
+const maxInt = int(^uint(0) >> 1)
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWord(c byte) bool {
+	return isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
@@ -0,0 +1,65 @@
+package parsing
+
+import (
+	"bufio"
+	"io"
+	"strings"
+)
+
+// Record is a group of lines between blank lines, like the items of
+// an Elf in day 1 or the drawing and the procedure in day 5
+type Record struct {
+	// Line is the number of the first line of the record. An empty
+	// record, found between two blank lines in a row, gets the number
+	// of the blank line ending it.
+	Line  int
+	Lines []string
+}
+
+// RecordScanner reads records separated by blank lines, a line with
+// nothing but spaces counting as blank. It is used like bufio.Scanner.
+type RecordScanner struct {
+	scanner *bufio.Scanner
+	line    int
+	record  Record
+}
+
+func NewRecordScanner(r io.Reader) *RecordScanner {
+	return &RecordScanner{scanner: bufio.NewScanner(r)}
+}
+
+// Scan moves to the next record and reports whether there was one.
+// A blank line at the end of the input does not start another record.
+func (s *RecordScanner) Scan() bool {
+	s.record = Record{}
+	for s.scanner.Scan() {
+		s.line++
+		line := s.scanner.Text()
+		if len(s.record.Lines) == 0 {
+			s.record.Line = s.line
+		}
+		if strings.TrimSpace(line) == "" {
+			return true
+		}
+		s.record.Lines = append(s.record.Lines, line)
+	}
+	return len(s.record.Lines) > 0
+}
+
+func (s *RecordScanner) Record() Record {
+	return s.record
+}
+
+func (s *RecordScanner) Err() error {
+	return s.scanner.Err()
+}
+
+// ReadLines returns all the lines of the input
+func ReadLines(r io.Reader) ([]string, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
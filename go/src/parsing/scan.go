@@ -0,0 +1,326 @@
+package parsing
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// kind is what a placeholder matches
+type kind int
+
+const (
+	// number is an integer with an optional sign, like 42 or -7
+	number kind = iota
+	// char is a single character
+	char
+	// text runs up to the literal text following the placeholder,
+	// to the next space when another placeholder follows, or to the
+	// end of the line
+	text
+)
+
+// segment is a piece of a pattern, literal text or a placeholder
+type segment struct {
+	literal     string
+	placeholder string
+}
+
+// Pattern is a compiled line pattern like "move {n} from {n} to {n}".
+// Placeholders are written between braces, everything else must
+// appear in the line as it is.
+type Pattern struct {
+	source   string
+	segments []segment
+	names    []string
+}
+
+// Compile checks the pattern and splits it into literal text and
+// placeholders. Two braces, {{ or }}, stand for a literal brace.
+func Compile(pattern string) (*Pattern, error) {
+	p := &Pattern{source: pattern}
+	var literal strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; {
+		case (c == '{' || c == '}') && i+1 < len(pattern) && pattern[i+1] == c:
+			literal.WriteByte(c)
+			i++
+		case c == '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("pattern %q: unclosed placeholder at %d", pattern, i+1)
+			}
+			name := pattern[i+1 : i+end]
+			if name == "" || strings.ContainsAny(name, "{ ") {
+				return nil, fmt.Errorf("pattern %q: invalid placeholder at %d", pattern, i+1)
+			}
+			if literal.Len() > 0 {
+				p.segments = append(p.segments, segment{literal: literal.String()})
+				literal.Reset()
+			}
+			p.segments = append(p.segments, segment{placeholder: name})
+			p.names = append(p.names, name)
+			i += end
+		case c == '}':
+			return nil, fmt.Errorf("pattern %q: unexpected } at %d", pattern, i+1)
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	if literal.Len() > 0 {
+		p.segments = append(p.segments, segment{literal: literal.String()})
+	}
+	return p, nil
+}
+
+// MustCompile is Compile for patterns known to be valid
+func MustCompile(pattern string) *Pattern {
+	p, err := Compile(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func (p *Pattern) String() string {
+	return p.source
+}
+
+var compiled sync.Map
+
+func cached(pattern string) (*Pattern, error) {
+	if p, ok := compiled.Load(pattern); ok {
+		return p.(*Pattern), nil
+	}
+	p, err := Compile(pattern)
+	if err != nil {
+		return nil, err
+	}
+	compiled.Store(pattern, p)
+	return p, nil
+}
+
+// field is the part of a line matched by a placeholder
+type field struct {
+	value  string
+	column int
+}
+
+// describe shows what was found at a position for error messages
+func describe(rest string) string {
+	if rest == "" {
+		return "end of line"
+	}
+	if end := strings.IndexByte(rest[1:], ' '); end >= 0 {
+		rest = rest[:end+1]
+	}
+	return strconv.Quote(rest)
+}
+
+// match splits the line into the fields of the placeholders,
+// which match the given kinds
+func (p *Pattern) match(line string, kinds []kind) ([]field, error) {
+	fields := make([]field, 0, len(kinds))
+	pos := 0
+	for i, seg := range p.segments {
+		if seg.placeholder == "" {
+			if !strings.HasPrefix(line[pos:], seg.literal) {
+				return nil, errorAt(pos+1, "expected %q, found %s", seg.literal, describe(line[pos:]))
+			}
+			pos += len(seg.literal)
+			continue
+		}
+
+		start := pos
+		switch kinds[len(fields)] {
+		case number:
+			if pos < len(line) && (line[pos] == '-' || line[pos] == '+') {
+				pos++
+			}
+			digits := pos
+			for pos < len(line) && line[pos] >= '0' && line[pos] <= '9' {
+				pos++
+			}
+			if pos == digits {
+				return nil, errorAt(start+1, "expected a number, found %s", describe(line[start:]))
+			}
+		case char:
+			if pos == len(line) {
+				return nil, errorAt(pos+1, "expected a character, found end of line")
+			}
+			pos++
+		case text:
+			end := len(line) - pos
+			if i+1 < len(p.segments) {
+				next := p.segments[i+1]
+				if next.literal != "" {
+					end = strings.Index(line[pos:], next.literal)
+				} else {
+					end = strings.IndexByte(line[pos:], ' ')
+				}
+				if end < 0 {
+					end = len(line) - pos
+				}
+			}
+			if end == 0 {
+				return nil, errorAt(start+1, "expected {%s}, found %s", seg.placeholder, describe(line[start:]))
+			}
+			pos += end
+		}
+		fields = append(fields, field{line[start:pos], start + 1})
+	}
+	if pos < len(line) {
+		return nil, errorAt(pos+1, "unexpected %q at the end of the line", line[pos:])
+	}
+	return fields, nil
+}
+
+func parseNumber(f field) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(f.value, "+"))
+	if err != nil {
+		return 0, errorAt(f.column, "number %s is out of range", f.value)
+	}
+	return n, nil
+}
+
+// Scan matches the line against the pattern and stores what the
+// placeholders matched in the targets, in order:
+//
+//	{n} an integer, into an *int
+//	{c} a single character, into a *byte
+//	{s} text, into a *string
+//
+// Errors give the column where the line stopped matching.
+func Scan(line string, pattern string, targets ...any) error {
+	p, err := cached(pattern)
+	if err != nil {
+		return err
+	}
+	return p.Scan(line, targets...)
+}
+
+// Scan matches the line against the pattern, see the Scan function
+func (p *Pattern) Scan(line string, targets ...any) error {
+	if len(targets) != len(p.names) {
+		return fmt.Errorf("pattern %q has %d placeholders but got %d targets", p.source, len(p.names), len(targets))
+	}
+
+	kinds := make([]kind, len(p.names))
+	for i, name := range p.names {
+		var ok bool
+		switch name {
+		case "n":
+			_, ok = targets[i].(*int)
+			kinds[i] = number
+		case "c":
+			_, ok = targets[i].(*byte)
+			kinds[i] = char
+		case "s":
+			_, ok = targets[i].(*string)
+			kinds[i] = text
+		default:
+			return fmt.Errorf("pattern %q: unknown placeholder {%s}, expected {n}, {c} or {s}", p.source, name)
+		}
+		if !ok {
+			return fmt.Errorf("pattern %q: cannot store {%s} in a %T", p.source, name, targets[i])
+		}
+	}
+
+	fields, err := p.match(line, kinds)
+	if err != nil {
+		return err
+	}
+	for i, f := range fields {
+		switch target := targets[i].(type) {
+		case *int:
+			n, err := parseNumber(f)
+			if err != nil {
+				return err
+			}
+			*target = n
+		case *byte:
+			*target = f.value[0]
+		case *string:
+			*target = f.value
+		}
+	}
+	return nil
+}
+
+// Decode matches the line against the pattern and stores the named
+// placeholders in the fields of the struct v points to. A placeholder
+// {name} goes to the field tagged `parse:"name"`, or else to the field
+// called like it. Integer fields match numbers, byte fields a single
+// character and string fields text.
+func Decode(line string, pattern string, v any) error {
+	p, err := cached(pattern)
+	if err != nil {
+		return err
+	}
+
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot decode into a %T, expected a pointer to a struct", v)
+	}
+	target = target.Elem()
+
+	values := make([]reflect.Value, len(p.names))
+	kinds := make([]kind, len(p.names))
+	for i, name := range p.names {
+		value, ok := structField(target, name)
+		if !ok {
+			return fmt.Errorf("pattern %q: %s has no settable field for {%s}", p.source, target.Type(), name)
+		}
+		switch value.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			kinds[i] = number
+		case reflect.Uint8:
+			kinds[i] = char
+		case reflect.String:
+			kinds[i] = text
+		default:
+			return fmt.Errorf("pattern %q: cannot decode {%s} into a %s", p.source, name, value.Type())
+		}
+		values[i] = value
+	}
+
+	fields, err := p.match(line, kinds)
+	if err != nil {
+		return err
+	}
+	for i, f := range fields {
+		switch kinds[i] {
+		case number:
+			n, err := parseNumber(f)
+			if err != nil {
+				return err
+			}
+			if values[i].OverflowInt(int64(n)) {
+				return errorAt(f.column, "number %s is out of range", f.value)
+			}
+			values[i].SetInt(int64(n))
+		case char:
+			values[i].SetUint(uint64(f.value[0]))
+		case text:
+			values[i].SetString(f.value)
+		}
+	}
+	return nil
+}
+
+func structField(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if tag, ok := t.Field(i).Tag.Lookup("parse"); ok && tag == name && v.Field(i).CanSet() {
+			return v.Field(i), true
+		}
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if strings.EqualFold(t.Field(i).Name, name) && v.Field(i).CanSet() {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}